@@ -18,6 +18,141 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/account/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Opens a WebSocket connection pushing the events of the authenticated user as {\"type\": \"...\", \"data\": {...}, \"time\": \"...\"}.\n\"stop_out\" is sent when the daily loss trip the kill switch of the user and \"stop_out_reset\" when an admin\nlift it, both with the stop out as data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Account event stream",
+                "responses": {}
+            }
+        },
+        "/api/admin/users/{id}/risk-limits": {
+            "put": {
+                "description": "Change the pre-trade risk limits of the user (see /api/risk/limits), the limits left out keep their value\nand zero turn a limit off. Requires the admin key in the X-Admin-Key header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set the risk limits of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Risk limits",
+                        "name": "riskLimitsRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RiskLimitsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Risk limits updated",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid risk limits",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin key",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update the risk limits",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/users/{id}/stop-out/reset": {
+            "post": {
+                "description": "Lift the kill switch the user tripped today, they can place orders again for the rest of the UTC day\nand are not stopped out again before the next one. The user is sent a \"stop_out_reset\" event.\nRequires the admin key in the X-Admin-Key header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset the stop out of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stop out reset",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin key",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "User is not stopped out",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/api/auth/login": {
             "post": {
                 "description": "user login  email, and password",
@@ -112,7 +247,7 @@ const docTemplate = `{
         },
         "/api/market-data": {
             "get": {
-                "description": "Opens a WebSocket connection to stream real-time market data",
+                "description": "Opens a WebSocket connection to stream real-time market data.\nStreams are named like \"btcusdt@ticker\", \"btcusdt@bookTicker\", \"btcusdt@trade\" or \"btcusdt@kline_1m\".\nSubscriptions are managed by sending {\"method\": \"SUBSCRIBE\", \"params\": [\"btcusdt@ticker\"], \"id\": 1},\n\"UNSUBSCRIBE\" or \"LIST_SUBSCRIPTIONS\", each acknowledged with {\"result\": ..., \"id\": 1} or {\"error\": {\"code\": 1, \"msg\": \"...\"}, \"id\": 1}.\nStream messages are sent as {\"stream\": \"btcusdt@ticker\", \"data\": {...}}.\nWhen the upstream feed of a stream drops, {\"stream\": \"btcusdt@ticker\", \"status\": \"reconnecting\"} is sent\nand {\"stream\": \"btcusdt@ticker\", \"status\": \"resumed\"} once it's back, the socket stays open meanwhile.",
                 "consumes": [
                     "application/json"
                 ],
@@ -123,9 +258,37 @@ const docTemplate = `{
                     "market-data"
                 ],
                 "summary": "Get real-time market data stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated streams to subscribe on connect",
+                        "name": "streams",
+                        "in": "query"
+                    }
+                ],
                 "responses": {}
             }
         },
+        "/api/market-data/stats": {
+            "get": {
+                "description": "Monitoring stats of the upstream market data streams: connected clients, reconnect counts and dropped messages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "market-data"
+                ],
+                "summary": "Market data stream stats",
+                "responses": {
+                    "200": {
+                        "description": "Market data stats",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/api/market-live": {
             "get": {
                 "description": "HTML page to test WebSocket connection",
@@ -156,7 +319,7 @@ const docTemplate = `{
                         "BearerTokenAuth": []
                     }
                 ],
-                "description": "Place a buy/sell order with the given details and fetch market data from Binance API.",
+                "description": "Place a buy/sell order with the given details, priced at the order book of the market data provider.",
                 "consumes": [
                     "application/json"
                 ],
@@ -191,6 +354,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "422": {
+                        "description": "Insufficient funds",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Failed to fetch market data",
                         "schema": {
@@ -200,14 +369,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/order/trade-history": {
-            "get": {
+        "/api/order/group": {
+            "post": {
                 "security": [
                     {
                         "BearerTokenAuth": []
                     }
                 ],
-                "description": "Retrieve all buy/sell orders for the authenticated user.",
+                "description": "A bracket place an entry order (\"market\" or \"limit\") and once it's filled a stop and a target order on the opposite side.\nAn entry which end partially filled (cancelled, expired or out of liquidity) get exits for the volume it filled,\nand the group is cancelled when it end without fills.\nAn OCO place the stop and the target order on the given side right away.\nThe target is a limit order at the take profit price and the stop a stop market order at the stop price,\nor a stop limit order when a stop limit price is given. When one of them fill or trigger the other is cancelled.\nPrices and volume are rounded to the filters of the symbol as for a single order, and the group go through\nthe risk rules with its two legs at their highest price.",
                 "consumes": [
                     "application/json"
                 ],
@@ -217,22 +386,39 @@ const docTemplate = `{
                 "tags": [
                     "orders"
                 ],
-                "summary": "List all orders",
+                "summary": "Place a bracket or OCO order group",
+                "parameters": [
+                    {
+                        "description": "Order group details",
+                        "name": "orderGroupRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.OrderGroupRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Order list retrieved successfully",
+                        "description": "Order group placed successfully",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "400": {
-                        "description": "User ID not found in context",
+                        "description": "Invalid order group",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Insufficient funds",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve order list",
+                        "description": "Failed to fetch market data",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -240,14 +426,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/order/{id}": {
+        "/api/order/group/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerTokenAuth": []
                     }
                 ],
-                "description": "Retrieve the details of a specific order by order ID for the authenticated user.",
+                "description": "Retrieve a bracket or OCO order group by ID with its orders and the order which completed it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -257,11 +443,11 @@ const docTemplate = `{
                 "tags": [
                     "orders"
                 ],
-                "summary": "Get order details",
+                "summary": "Get order group details",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Order group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -269,19 +455,19 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order details retrieved successfully",
+                        "description": "Order group details retrieved successfully",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid order ID",
+                        "description": "Invalid order group ID",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve order details",
+                        "description": "Failed to retrieve order group details",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -294,7 +480,7 @@ const docTemplate = `{
                         "BearerTokenAuth": []
                     }
                 ],
-                "description": "Delete a specific order by order ID for the authenticated user.",
+                "description": "Cancel an active bracket or OCO order group with all its pending and open orders.",
                 "consumes": [
                     "application/json"
                 ],
@@ -304,11 +490,11 @@ const docTemplate = `{
                 "tags": [
                     "orders"
                 ],
-                "summary": "Delete an order",
+                "summary": "Cancel an order group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Order group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -316,67 +502,856 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order deleted successfully",
+                        "description": "Order group cancelled successfully",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid order ID",
+                        "description": "Order group is not active",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete order",
+                        "description": "Failed to cancel order group",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "request.LoginRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+        },
+        "/api/order/groups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Retrieve the bracket and OCO order groups of the authenticated user with their orders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List order groups",
+                "responses": {
+                    "200": {
+                        "description": "Order groups retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve order groups",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
                 }
             }
         },
-        "request.OrderRequest": {
-            "type": "object",
-            "required": [
-                "symbol",
-                "type",
-                "volume"
-            ],
-            "properties": {
-                "symbol": {
-                    "description": "Asset symbol (e.g., \"BTCUSDT\")",
-                    "type": "string"
-                },
-                "type": {
-                    "description": "Order type: \"buy\" or \"sell\"",
-                    "type": "string"
-                },
-                "volume": {
-                    "description": "Quantity to buy or sell",
-                    "type": "number"
+        "/api/order/trade-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Retrieve all buy/sell orders for the authenticated user.\nEnded orders report their endReason: \"filled\", \"triggered\", \"user_cancelled\", \"group_cancelled\", \"sibling_filled\",\n\"ioc_unfilled\", \"fok_unfilled\", \"expired\" or \"liquidity_exhausted\".\nEach order list its trades, a trade closing a position has its realized PnL and the tax lots it closed\nby the cost basis method of the user, with their open and close prices.\nEach trade report its liquidity (\"maker\" or \"taker\"), fee rate, fee and fee asset, and each order the total of its fees.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List all orders",
+                "responses": {
+                    "200": {
+                        "description": "Order list retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve order list",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
                 }
             }
         },
-        "request.RegisterUserRequest": {
-            "type": "object",
+        "/api/order/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Retrieve the details of a specific order by order ID for the authenticated user.\nTriggered orders include their trigger time and the orders they placed as children.\nTrailing stops include the best price seen so far and their current trigger price.\nThe amendments of the order are listed oldest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get order details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order details retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve order details",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Cancel a pending, open or partially filled order of the authenticated user. The order is kept in the history\nwith status \"cancelled\", filled orders can't be cancelled. Cancelling an order of an active group cancel the group,\nexcept a bracket entry, which end alone and leave the exits to what it filled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cancel an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order cancelled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Order can't be cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to cancel order",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Change the quantity, limit price or trigger price of a pending, open or partially filled order.\nThe fields not set are kept and every amendment is recorded in the order details.\nThe amended order go through the risk rules again, an amendment breaking them is refused with 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Amend an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New quantity and prices",
+                        "name": "amendOrderRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.AmendOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order amended successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Order can't be amended",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Risk rules broken",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch market data",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/portfolio/equity": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Equity of the authenticated user over time from the snapshots taken by the equity snapshot job, oldest first.\nEquity is the cash (USDT) plus the other assets valued at their bid when the snapshot was taken.\nWith an interval only the last snapshot of each interval from the start of the range is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get the equity curve",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the range (RFC 3339), 30 days before the end by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range (RFC 3339), now by default",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Spacing of the points, e.g. 1h or 24h",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Equity curve retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range or interval",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve the equity curve",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/portfolio/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Performance of the authenticated user over the window. Total and annualized return, volatility, Sharpe and Sortino\nratios, max drawdown and its duration come from the equity snapshots, the ratios annualized by their spacing.\nWin rate, average win and loss, profit factor and realized PnL come from the trades which closed lots in the window.\nA stat the window has too little data for is left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get the portfolio stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the window (RFC 3339), 30 days before the end by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the window (RFC 3339), now by default",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Annual risk free rate for the Sharpe and Sortino ratios, e.g. 0.04",
+                        "name": "riskFreeRate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Portfolio stats retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid window",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compute the portfolio stats",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/positions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "List the open positions of the authenticated user built from the fills of their orders, with the average entry price\nand the unrealized PnL at the current price, marked at the bid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "List open positions",
+                "responses": {
+                    "200": {
+                        "description": "Positions retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve positions",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risk/limits": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Get the pre-trade risk limits the orders of the authenticated user are checked against, \"custom\" is false\nwhile the user has the default limits. A limit of zero is not enforced. Every order, order group and amendment\ngo through the rules before anything is saved: \"max_order_notional\" cap the value of the order,\n\"max_position_size\" the value of the position in the symbol once a buy is filled, \"max_open_orders\" the orders\nwaiting to trigger or fill, \"max_daily_volume\" the value traded since the start of the UTC day with what the\norder has left to fill, and \"price_band\" how far a limit price may be from the mark price, as a fraction of it.\nAn order breaking rules is refused with 422 and the error list every rule it broke with its limit and the value\nthe order reached. \"maxDailyLoss\" is the realized and unrealized loss of a UTC day that stop the user out\n(see /api/risk/stop-outs), their positions are closed too when \"flattenOnStopOut\" is set.\nThe limits are set by an admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Get the risk limits",
+                "responses": {
+                    "200": {
+                        "description": "Risk limits retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve risk limits",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risk/stop-outs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "List the kill switches the daily loss of the authenticated user tripped, newest first. The loss is the realized PnL\nof the trades of the UTC day and the unrealized PnL, the change of the equity since its first snapshot of the day.\nA stop out cancel the live orders, close the positions when \"flattenOnStopOut\" is set, and refuse new orders\nwith 422 \"daily_loss_limit\" until the next UTC day or an admin reset (\"resetAt\").",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "List the stop outs",
+                "responses": {
+                    "200": {
+                        "description": "Stop outs retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve stop outs",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/symbols": {
+            "get": {
+                "description": "List the symbols of the symbol registry with their base and quote assets, status and exchange filters.\nOrder prices are rounded to the tick size and volumes down to the step size of their symbol, an order below\nthe min quantity or the min notional, or above the max quantity, is refused. A zero filter is not enforced.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "market-data"
+                ],
+                "summary": "List the symbols",
+                "responses": {
+                    "200": {
+                        "description": "Symbols retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve symbols",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "List the assets held by the authenticated user with their free balance and the balance locked by live orders.\nEach asset is valued in USDT at the current bid of its USDT market, an asset without a market price is listed\nwith \"priced\": false and left out of the total value.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get the wallet",
+                "responses": {
+                    "200": {
+                        "description": "Wallet retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve wallet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/cost-basis": {
+            "put": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "Set how the trades of the authenticated user close the tax lots of their positions and realize PnL.\n\"FIFO\" (default) close the oldest lots first, \"LIFO\" the newest lots first and \"AVERAGE\" every lot in proportion\nat their weighted average price. The lots already closed keep their matches.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Set the cost basis method",
+                "parameters": [
+                    {
+                        "description": "Cost basis method",
+                        "name": "costBasisRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CostBasisRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cost basis method updated",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cost basis method",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update the cost basis method",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/ledger": {
+            "get": {
+                "security": [
+                    {
+                        "BearerTokenAuth": []
+                    }
+                ],
+                "description": "List the ledger entries behind the wallet balances of the authenticated user, newest first.\nEvery deposit, reservation, release and fill post a journal of balanced entries, a debit add to the\n\"available\" or \"locked\" balance of the asset and a credit take from it. Entries reference the order they came from.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get the ledger",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only the entries of this asset (e.g., USDT)",
+                        "name": "asset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ledger retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve ledger",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "request.AmendOrderRequest": {
+            "type": "object",
+            "properties": {
+                "limitPrice": {
+                    "description": "New limit price of a limit or stop limit order",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "triggerPrice": {
+                    "description": "New trigger price of a stop or take profit order",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "volume": {
+                    "description": "New quantity, more than the quantity already filled",
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "request.CostBasisRequest": {
+            "type": "object",
+            "required": [
+                "method"
+            ],
+            "properties": {
+                "method": {
+                    "description": "\"FIFO\", \"LIFO\" or \"AVERAGE\"",
+                    "type": "string",
+                    "enum": [
+                        "FIFO",
+                        "LIFO",
+                        "AVERAGE"
+                    ]
+                }
+            }
+        },
+        "request.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.OrderGroupRequest": {
+            "type": "object",
+            "required": [
+                "groupType",
+                "stopPrice",
+                "symbol",
+                "takeProfitPrice",
+                "type",
+                "volume"
+            ],
+            "properties": {
+                "entryKind": {
+                    "description": "Bracket entry kind: \"market\" (default) or \"limit\"",
+                    "type": "string",
+                    "enum": [
+                        "market",
+                        "limit"
+                    ]
+                },
+                "entryPrice": {
+                    "description": "Limit price of a limit bracket entry",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "groupType": {
+                    "description": "Group type: \"bracket\" or \"oco\"",
+                    "type": "string",
+                    "enum": [
+                        "bracket",
+                        "oco"
+                    ]
+                },
+                "stopLimitPrice": {
+                    "description": "Limit price of the stop order, a stop market order when not set",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "stopPrice": {
+                    "description": "Trigger price of the stop order",
+                    "type": "number"
+                },
+                "symbol": {
+                    "description": "Asset symbol (e.g., \"BTCUSDT\")",
+                    "type": "string"
+                },
+                "takeProfitPrice": {
+                    "description": "Limit price of the target order",
+                    "type": "number"
+                },
+                "type": {
+                    "description": "Side of the bracket entry, or of both OCO orders: \"buy\" or \"sell\"",
+                    "type": "string",
+                    "enum": [
+                        "buy",
+                        "sell"
+                    ]
+                },
+                "volume": {
+                    "description": "Quantity of every order of the group",
+                    "type": "number"
+                }
+            }
+        },
+        "request.OrderRequest": {
+            "type": "object",
+            "required": [
+                "symbol",
+                "type",
+                "volume"
+            ],
+            "properties": {
+                "expiresAt": {
+                    "description": "Expiry time of a GTD order",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Order kind: \"market\" (default), \"limit\", \"stop_market\", \"stop_limit\", \"take_profit\" or \"trailing_stop\"",
+                    "type": "string",
+                    "enum": [
+                        "market",
+                        "limit",
+                        "stop_market",
+                        "stop_limit",
+                        "take_profit",
+                        "trailing_stop"
+                    ]
+                },
+                "limitPrice": {
+                    "description": "Limit price, required for limit and stop limit orders",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "symbol": {
+                    "description": "Asset symbol (e.g., \"BTCUSDT\")",
+                    "type": "string"
+                },
+                "timeInForce": {
+                    "description": "Time in force: \"GTC\" (default), \"IOC\", \"FOK\" or \"GTD\"",
+                    "type": "string",
+                    "enum": [
+                        "GTC",
+                        "IOC",
+                        "FOK",
+                        "GTD"
+                    ]
+                },
+                "trailingOffset": {
+                    "description": "Absolute distance of a trailing stop trigger from the best price",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "trailingPercent": {
+                    "description": "Distance of a trailing stop trigger in percent of the best price, instead of the absolute offset",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "triggerPrice": {
+                    "description": "Trigger price, required for stop and take profit orders",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "type": {
+                    "description": "Order type: \"buy\" or \"sell\"",
+                    "type": "string"
+                },
+                "volume": {
+                    "description": "Quantity to buy or sell",
+                    "type": "number"
+                }
+            }
+        },
+        "request.RegisterUserRequest": {
+            "type": "object",
             "required": [
                 "confirm_password",
                 "email",
@@ -405,6 +1380,45 @@ const docTemplate = `{
                 }
             }
         },
+        "request.RiskLimitsRequest": {
+            "type": "object",
+            "properties": {
+                "flattenOnStopOut": {
+                    "description": "Close the positions when the user is stopped out",
+                    "type": "boolean"
+                },
+                "maxDailyLoss": {
+                    "description": "Realized and unrealized loss of a UTC day that stop the user out",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "maxDailyVolume": {
+                    "description": "Max value traded in a UTC day in the quote asset",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "maxOpenOrders": {
+                    "description": "Max orders waiting to trigger or fill",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "maxOrderNotional": {
+                    "description": "Max value of one order in the quote asset",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "maxPositionSize": {
+                    "description": "Max value of the position in a symbol in the quote asset",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "priceBand": {
+                    "description": "Max distance of a limit price from the mark price, e.g. 0.05 for 5%",
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
         "response.Response": {
             "type": "object",
             "properties": {
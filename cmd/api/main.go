@@ -15,12 +15,15 @@ func main() {
 		log.Fatal("Error to load the config: ", err)
 	}
 
-	server, err := di.InitializeApi(cfg)
+	server, cleanup, err := di.InitializeApi(cfg)
 	if err != nil {
 		log.Fatal("Failed to initialize the api: ", err)
 	}
 
-	if server.Start(); err != nil {
+	// the cleanup run before exiting, log.Fatal would skip a defer
+	err = server.Start()
+	cleanup()
+	if err != nil {
 		log.Fatal("failed to start server: ", err)
 	}
 }
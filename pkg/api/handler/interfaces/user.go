@@ -7,10 +7,32 @@ type UserHandler interface {
 	Login(ctx *gin.Context)
 
 	StreamMarketData(c *gin.Context)
+	MarketDataStats(c *gin.Context)
 	WebSocketTestPage(c *gin.Context)
+	ListSymbols(c *gin.Context)
 
 	OrderHandler(c *gin.Context)
 	AllOrders(c *gin.Context)
 	OrderDetails(c *gin.Context)
-	DeteleTrade(c *gin.Context)
+	CancelOrder(c *gin.Context)
+	AmendOrder(c *gin.Context)
+
+	PlaceOrderGroup(c *gin.Context)
+	ListOrderGroups(c *gin.Context)
+	OrderGroupDetails(c *gin.Context)
+	CancelOrderGroup(c *gin.Context)
+
+	Wallet(c *gin.Context)
+	Ledger(c *gin.Context)
+	SetCostBasis(c *gin.Context)
+
+	RiskLimits(c *gin.Context)
+	SetRiskLimits(c *gin.Context)
+	StopOuts(c *gin.Context)
+	ResetStopOut(c *gin.Context)
+	AccountEvents(c *gin.Context)
+
+	Positions(c *gin.Context)
+	EquityCurve(c *gin.Context)
+	PortfolioStats(c *gin.Context)
 }
@@ -1,20 +1,24 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
 	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
 	"github.com/kannan112/mock-trading-platform-api/pkg/api/middleware"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
 )
 
-var binanceURL = "wss://stream.binance.com:9443/ws/btcusdt@ticker"
+// maximum number of streams a market data socket can subscribe to
+const maxStreamSubscriptions = 50
 
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
@@ -26,18 +30,20 @@ var upgrader = websocket.Upgrader{
 
 // WebSocket Market Data Stream godoc
 // @Summary Get real-time market data stream
-// @Description Opens a WebSocket connection to stream real-time market data
+// @Description Opens a WebSocket connection to stream real-time market data.
+// @Description Streams are named like "btcusdt@ticker", "btcusdt@bookTicker", "btcusdt@trade" or "btcusdt@kline_1m".
+// @Description Subscriptions are managed by sending {"method": "SUBSCRIBE", "params": ["btcusdt@ticker"], "id": 1},
+// @Description "UNSUBSCRIBE" or "LIST_SUBSCRIPTIONS", each acknowledged with {"result": ..., "id": 1} or {"error": {"code": 1, "msg": "..."}, "id": 1}.
+// @Description Stream messages are sent as {"stream": "btcusdt@ticker", "data": {...}}.
+// @Description When the upstream feed of a stream drops, {"stream": "btcusdt@ticker", "status": "reconnecting"} is sent
+// @Description and {"stream": "btcusdt@ticker", "status": "resumed"} once it's back, the socket stays open meanwhile.
 // @Tags market-data
 // @Accept  json
 // @Produce  json
+// @Param streams query string false "Comma separated streams to subscribe on connect"
 // @Router /api/market-data [get]
 func (h *UserHandler) StreamMarketData(c *gin.Context) {
 
-	if h.clients == nil {
-		h.clientsMux.Lock()
-		h.clients = make(map[*websocket.Conn]bool)
-		h.clientsMux.Unlock()
-	}
 	clientWS, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("Failed to upgrade client connection: %v", err)
@@ -45,28 +51,186 @@ func (h *UserHandler) StreamMarketData(c *gin.Context) {
 	}
 	defer clientWS.Close()
 
-	// Binance WebSocket connection
-	binanceWS, _, err := websocket.DefaultDialer.Dial(binanceURL, nil)
+	client := h.marketHub.Register()
+	defer h.marketHub.Unregister(client)
+
+	// replies to the control messages, written by the same loop as the stream messages
+	replies := make(chan interface{}, 16)
+
+	// the gin context is not safe to share with the reader goroutine
+	ctx := c.Request.Context()
+
+	// streams to subscribe on connect
+	if streams := c.Query("streams"); streams != "" {
+		replies <- h.handleStreamRequest(ctx, client, request.StreamRequest{
+			Method: request.StreamMethodSubscribe,
+			Params: strings.Split(streams, ","),
+		})
+	}
+
+	// read the control messages until the client is gone
+	go func() {
+		defer h.marketHub.Unregister(client)
+		for {
+			_, message, err := clientWS.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var (
+				body  request.StreamRequest
+				reply interface{}
+			)
+			if err := json.Unmarshal(message, &body); err != nil {
+				reply = streamError(0, response.StreamErrorInvalidRequest, "invalid JSON request")
+			} else {
+				reply = h.handleStreamRequest(ctx, client, body)
+			}
+
+			select {
+			case replies <- reply:
+			case <-client.Done():
+				return
+			}
+		}
+	}()
+
+	// Listen for messages from the hub and forward to client
+	for {
+		select {
+		case <-client.Done():
+			return
+		case reply := <-replies:
+			if err := clientWS.WriteJSON(reply); err != nil {
+				log.Printf("Failed to send reply to client: %v", err)
+				return
+			}
+		case message := <-client.Messages():
+			stream := marketdata.StreamName(message.Symbol, message.Kind)
+
+			// Send the provider message or the stream status to the connected client
+			if message.Status != "" {
+				err = clientWS.WriteJSON(response.StreamStatus{Stream: stream, Status: message.Status})
+			} else {
+				err = clientWS.WriteJSON(response.StreamData{Stream: stream, Data: message.Data})
+			}
+			if err != nil {
+				log.Printf("Failed to send message to client: %v", err)
+				return
+			}
+		}
+	}
+}
+
+// MarketDataStats godoc
+// @Summary Market data stream stats
+// @Description Monitoring stats of the upstream market data streams: connected clients, reconnect counts and dropped messages.
+// @Tags market-data
+// @Produce json
+// @Success 200 {object} response.Response "Market data stats"
+// @Router /api/market-data/stats [get]
+func (h *UserHandler) MarketDataStats(c *gin.Context) {
+	response.SuccessResponse(c, "market data stats", h.marketHub.Stats())
+}
+
+// ListSymbols godoc
+// @Summary List the symbols
+// @Description List the symbols of the symbol registry with their base and quote assets, status and exchange filters.
+// @Description Order prices are rounded to the tick size and volumes down to the step size of their symbol, an order below
+// @Description the min quantity or the min notional, or above the max quantity, is refused. A zero filter is not enforced.
+// @Tags market-data
+// @Produce json
+// @Success 200 {object} response.Response "Symbols retrieved successfully"
+// @Failure 500 {object} response.Response "Failed to retrieve symbols"
+// @Router /api/symbols [get]
+func (h *UserHandler) ListSymbols(c *gin.Context) {
+
+	symbols, err := h.symbolUseCase.ListSymbols(c)
 	if err != nil {
-		log.Printf("Failed to connect to Binance WebSocket: %v", err)
+		response.ErrorResponse(c, "Failed to retrieve symbols", err, nil)
 		return
 	}
-	defer binanceWS.Close()
 
-	// Listen for messages from Binance WebSocket and forward to client
-	for {
-		_, message, err := binanceWS.ReadMessage()
+	response.SuccessResponse(c, "Symbols retrieved successfully", symbols)
+}
+
+// apply a control message of the market data socket and return its reply
+func (h *UserHandler) handleStreamRequest(ctx context.Context, client *marketdata.Client, body request.StreamRequest) interface{} {
+
+	switch body.Method {
+	case request.StreamMethodList:
+		return response.StreamResponse{ID: body.ID, Result: h.marketHub.Subscriptions(client)}
+	case request.StreamMethodSubscribe, request.StreamMethodUnsubscribe:
+	default:
+		return streamError(body.ID, response.StreamErrorInvalidRequest, fmt.Sprintf("unknown method %q", body.Method))
+	}
+
+	if len(body.Params) == 0 {
+		return streamError(body.ID, response.StreamErrorInvalidRequest, "params should have at least one stream")
+	}
+
+	type stream struct {
+		symbol string
+		kind   marketdata.StreamKind
+	}
+	streams := make([]stream, len(body.Params))
+	for i, name := range body.Params {
+		symbol, kind, err := marketdata.ParseStreamName(strings.TrimSpace(name))
 		if err != nil {
-			log.Printf("Error reading Binance WebSocket message: %v", err)
-			return
+			return streamError(body.ID, response.StreamErrorInvalidStream, err.Error())
 		}
+		streams[i] = stream{symbol: symbol, kind: kind}
+	}
 
-		// Send the Binance message to the connected client
-		err = clientWS.WriteMessage(websocket.TextMessage, message)
+	// streams are keyed by the registry symbol the name resolve to
+	var unsupported error
+	for i, s := range streams {
+		symbol, err := h.userUseCase.GetSymbolInfo(ctx, s.symbol)
 		if err != nil {
-			log.Printf("Failed to send message to client: %v", err)
-			return
+			unsupported = err
+			continue
+		}
+		streams[i].symbol = symbol.Symbol
+	}
+
+	if body.Method == request.StreamMethodUnsubscribe {
+		for _, s := range streams {
+			h.marketHub.Unsubscribe(client, s.symbol, s.kind)
 		}
+		return response.StreamResponse{ID: body.ID}
+	}
+	if unsupported != nil {
+		return streamError(body.ID, response.StreamErrorUnsupportedSymbol, unsupported.Error())
+	}
+
+	existing := make(map[string]bool)
+	for _, name := range h.marketHub.Subscriptions(client) {
+		existing[name] = true
+	}
+	if len(existing)+len(streams) > maxStreamSubscriptions {
+		return streamError(body.ID, response.StreamErrorTooManySubscriptions,
+			fmt.Sprintf("a connection can subscribe to at most %d streams", maxStreamSubscriptions))
+	}
+
+	for i, s := range streams {
+		if err := h.marketHub.Subscribe(client, s.symbol, s.kind); err != nil {
+			// roll back the new subscriptions of this request
+			for _, done := range streams[:i] {
+				if !existing[marketdata.StreamName(done.symbol, done.kind)] {
+					h.marketHub.Unsubscribe(client, done.symbol, done.kind)
+				}
+			}
+			return streamError(body.ID, response.StreamErrorSubscribeFailed, err.Error())
+		}
+	}
+
+	return response.StreamResponse{ID: body.ID}
+}
+
+func streamError(id int64, code int, message string) response.StreamErrorResponse {
+	return response.StreamErrorResponse{
+		ID:    id,
+		Error: response.StreamError{Code: code, Msg: message},
 	}
 }
 
@@ -106,7 +270,7 @@ func (h *UserHandler) WebSocketTestPage(c *gin.Context) {
 
 // OrderHandler godoc
 // @Summary Place an order
-// @Description Place a buy/sell order with the given details and fetch market data from Binance API.
+// @Description Place a buy/sell order with the given details, priced at the order book of the market data provider.
 // @Tags orders
 // @Accept json
 // @Security BearerTokenAuth
@@ -114,6 +278,7 @@ func (h *UserHandler) WebSocketTestPage(c *gin.Context) {
 // @Param orderRequest body request.OrderRequest true "Order request details"
 // @Success 200 {object} response.Response "Order placed successfully"
 // @Failure 400 {object} response.Response "Invalid order type"
+// @Failure 422 {object} response.Response "Insufficient funds"
 // @Failure 500 {object} response.Response "Failed to fetch market data"
 // @Router /api/order [post]
 func (h *UserHandler) OrderHandler(ctx *gin.Context) {
@@ -136,36 +301,13 @@ func (h *UserHandler) OrderHandler(ctx *gin.Context) {
 		return
 	}
 
-	marketData, err := h.userUseCase.FetchMarketData(orderRequest.Symbol)
-	if err != nil {
-		response.ErrorResponse(ctx, "Failed to fetch market data", err, nil)
-		return
-	}
-
-	price, err := h.userUseCase.GetMarketPrice(marketData, orderRequest.Type)
-	if err != nil {
-		response.ErrorResponse(ctx, "Failed to get market price", err, nil)
-		return
-	}
-	fmt.Println(price, "price")
-
-	orderUUID := uuid.New().String()
-	orderResponse := response.OrderResponse{
-		OrderUUID: orderUUID,
-		Symbol:    orderRequest.Symbol,
-		Volume:    orderRequest.Volume,
-		Price:     price,
-		Type:      orderRequest.Type,
-		Status:    "accepted",
-	}
-	oid, err := h.userUseCase.CreateOrder(ctx, uid, orderResponse)
+	orderResponse, err := h.orderUseCase.PlaceOrder(ctx, uid, orderRequest)
 	if err != nil {
 		response.ErrorResponse(ctx, "failed to create order", err, nil)
 		return
 	}
-	orderResponse.OrderID = uint(oid)
 
-	response.SuccessResponse(ctx, "order completed", orderResponse)
+	response.SuccessResponse(ctx, "order placed", orderResponse)
 }
 
 // AllOrders godoc
@@ -174,6 +316,11 @@ func (h *UserHandler) OrderHandler(ctx *gin.Context) {
 // @Security BearerTokenAuth
 //
 // @Description Retrieve all buy/sell orders for the authenticated user.
+// @Description Ended orders report their endReason: "filled", "triggered", "user_cancelled", "group_cancelled", "sibling_filled",
+// @Description "ioc_unfilled", "fok_unfilled", "expired" or "liquidity_exhausted".
+// @Description Each order list its trades, a trade closing a position has its realized PnL and the tax lots it closed
+// @Description by the cost basis method of the user, with their open and close prices.
+// @Description Each trade report its liquidity ("maker" or "taker"), fee rate, fee and fee asset, and each order the total of its fees.
 // @Tags orders
 // @Accept json
 // @Produce json
@@ -188,7 +335,7 @@ func (h *UserHandler) AllOrders(c *gin.Context) {
 		response.ErrorResponse(c, "Faild to get user id from context", err, nil)
 		return
 	}
-	data, err := h.userUseCase.ListOrders(uid)
+	data, err := h.orderUseCase.ListOrders(c, uid)
 	if err != nil {
 		response.ErrorResponse(c, "Failed to retrieve order list", err, nil)
 		return
@@ -200,6 +347,9 @@ func (h *UserHandler) AllOrders(c *gin.Context) {
 // OrderDetails godoc
 // @Summary Get order details
 // @Description Retrieve the details of a specific order by order ID for the authenticated user.
+// @Description Triggered orders include their trigger time and the orders they placed as children.
+// @Description Trailing stops include the best price seen so far and their current trigger price.
+// @Description The amendments of the order are listed oldest first.
 // @Tags orders
 // @Accept json
 // @Security BearerTokenAuth
@@ -221,7 +371,7 @@ func (h *UserHandler) OrderDetails(c *gin.Context) {
 		response.ErrorResponse(c, "Faild to get user id from context", err, nil)
 	}
 
-	data, err := h.userUseCase.GetOrderByID(c, uint(uid), uint(orderid))
+	data, err := h.orderUseCase.GetOrderByID(c, uint(uid), uint(orderid))
 	if err != nil {
 		response.ErrorResponse(c, "failed to get order details", err, nil)
 		return
@@ -229,34 +379,79 @@ func (h *UserHandler) OrderDetails(c *gin.Context) {
 	response.SuccessResponse(c, "order details", data)
 }
 
-// DeteleTrade godoc
-// @Summary Delete an order
-// @Description Delete a specific order by order ID for the authenticated user.
+// CancelOrder godoc
+// @Summary Cancel an order
+// @Description Cancel a pending, open or partially filled order of the authenticated user. The order is kept in the history
+// @Description with status "cancelled", filled orders can't be cancelled. Cancelling an order of an active group cancel the group,
+// @Description except a bracket entry, which end alone and leave the exits to what it filled.
 // @Tags orders
 // @Accept json
 // @Security BearerTokenAuth
 // @Produce json
 // @Param id path int true "Order ID"
-// @Success 200 {object} response.Response "Order deleted successfully"
-// @Failure 400 {object} response.Response "Invalid order ID"
-// @Failure 500 {object} response.Response "Failed to delete order"
+// @Success 200 {object} response.Response "Order cancelled successfully"
+// @Failure 400 {object} response.Response "Order can't be cancelled"
+// @Failure 500 {object} response.Response "Failed to cancel order"
 // @Router /api/order/{id} [delete]
-func (h *UserHandler) DeteleTrade(c *gin.Context) {
+func (h *UserHandler) CancelOrder(c *gin.Context) {
 	idStr := c.Param("id")
 	orderid, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.ErrorResponse(c, "Invalid order id", err, nil)
+		return
+	}
+	uid, err := middleware.GetUserIdFromContext(c)
 	if err != nil {
 		response.ErrorResponse(c, "Faild to get user id from context", err, nil)
 		return
 	}
+
+	err = h.orderUseCase.CancelOrder(c, uint(uid), uint(orderid))
+	if err != nil {
+		response.ErrorResponse(c, "failed to cancel order", err, nil)
+		return
+	}
+	response.SuccessResponse(c, "order cancelled")
+}
+
+// AmendOrder godoc
+// @Summary Amend an order
+// @Description Change the quantity, limit price or trigger price of a pending, open or partially filled order.
+// @Description The fields not set are kept and every amendment is recorded in the order details.
+// @Description The amended order go through the risk rules again, an amendment breaking them is refused with 422.
+// @Tags orders
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param amendOrderRequest body request.AmendOrderRequest true "New quantity and prices"
+// @Success 200 {object} response.Response "Order amended successfully"
+// @Failure 400 {object} response.Response "Order can't be amended"
+// @Failure 422 {object} response.Response "Risk rules broken"
+// @Failure 500 {object} response.Response "Failed to fetch market data"
+// @Router /api/order/{id} [patch]
+func (h *UserHandler) AmendOrder(c *gin.Context) {
+	orderid, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.ErrorResponse(c, "Invalid order id", err, nil)
+		return
+	}
 	uid, err := middleware.GetUserIdFromContext(c)
 	if err != nil {
 		response.ErrorResponse(c, "Faild to get user id from context", err, nil)
+		return
 	}
 
-	err = h.userUseCase.DeleteOrderById(c, uint(uid), uint(orderid))
+	var body request.AmendOrderRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.ErrorResponse(c, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	order, err := h.orderUseCase.AmendOrder(c, uint(uid), uint(orderid), body)
 	if err != nil {
-		response.ErrorResponse(c, "failed to get order details", err, nil)
+		response.ErrorResponse(c, "failed to amend order", err, nil)
 		return
 	}
-	response.SuccessResponse(c, "order deleted")
+	response.SuccessResponse(c, "order amended", order)
 }
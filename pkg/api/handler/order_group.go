@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/middleware"
+)
+
+// PlaceOrderGroup godoc
+// @Summary Place a bracket or OCO order group
+// @Description A bracket place an entry order ("market" or "limit") and once it's filled a stop and a target order on the opposite side.
+// @Description An entry which end partially filled (cancelled, expired or out of liquidity) get exits for the volume it filled,
+// @Description and the group is cancelled when it end without fills.
+// @Description An OCO place the stop and the target order on the given side right away.
+// @Description The target is a limit order at the take profit price and the stop a stop market order at the stop price,
+// @Description or a stop limit order when a stop limit price is given. When one of them fill or trigger the other is cancelled.
+// @Description Prices and volume are rounded to the filters of the symbol as for a single order, and the group go through
+// @Description the risk rules with its two legs at their highest price.
+// @Tags orders
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Param orderGroupRequest body request.OrderGroupRequest true "Order group details"
+// @Success 200 {object} response.Response "Order group placed successfully"
+// @Failure 400 {object} response.Response "Invalid order group"
+// @Failure 422 {object} response.Response "Insufficient funds"
+// @Failure 500 {object} response.Response "Failed to fetch market data"
+// @Router /api/order/group [post]
+func (h *UserHandler) PlaceOrderGroup(ctx *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(ctx)
+	if err != nil {
+		response.ErrorResponse(ctx, "Failed to get userid from context", err, nil)
+		return
+	}
+
+	var body request.OrderGroupRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.ErrorResponse(ctx, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	group, err := h.orderUseCase.PlaceOrderGroup(ctx, uid, body)
+	if err != nil {
+		response.ErrorResponse(ctx, "failed to create order group", err, nil)
+		return
+	}
+
+	response.SuccessResponse(ctx, "order group placed", group)
+}
+
+// ListOrderGroups godoc
+// @Summary List order groups
+// @Description Retrieve the bracket and OCO order groups of the authenticated user with their orders.
+// @Tags orders
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Success 200 {object} response.Response "Order groups retrieved successfully"
+// @Failure 400 {object} response.Response "User ID not found in context"
+// @Failure 500 {object} response.Response "Failed to retrieve order groups"
+// @Router /api/order/groups [get]
+func (h *UserHandler) ListOrderGroups(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	data, err := h.orderUseCase.ListOrderGroups(c, uint(uid))
+	if err != nil {
+		response.ErrorResponse(c, "Failed to retrieve order groups", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Order groups retrieved successfully", data)
+}
+
+// OrderGroupDetails godoc
+// @Summary Get order group details
+// @Description Retrieve a bracket or OCO order group by ID with its orders and the order which completed it.
+// @Tags orders
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Param id path int true "Order group ID"
+// @Success 200 {object} response.Response "Order group details retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid order group ID"
+// @Failure 500 {object} response.Response "Failed to retrieve order group details"
+// @Router /api/order/group/{id} [get]
+func (h *UserHandler) OrderGroupDetails(c *gin.Context) {
+
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.ErrorResponse(c, "Invalid order group id", err, nil)
+		return
+	}
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	data, err := h.orderUseCase.GetOrderGroup(c, uint(uid), uint(groupID))
+	if err != nil {
+		response.ErrorResponse(c, "failed to get order group details", err, nil)
+		return
+	}
+	response.SuccessResponse(c, "order group details", data)
+}
+
+// CancelOrderGroup godoc
+// @Summary Cancel an order group
+// @Description Cancel an active bracket or OCO order group with all its pending and open orders.
+// @Tags orders
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Param id path int true "Order group ID"
+// @Success 200 {object} response.Response "Order group cancelled successfully"
+// @Failure 400 {object} response.Response "Order group is not active"
+// @Failure 500 {object} response.Response "Failed to cancel order group"
+// @Router /api/order/group/{id} [delete]
+func (h *UserHandler) CancelOrderGroup(c *gin.Context) {
+
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.ErrorResponse(c, "Invalid order group id", err, nil)
+		return
+	}
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	if err := h.orderUseCase.CancelOrderGroup(c, uint(uid), uint(groupID)); err != nil {
+		response.ErrorResponse(c, "failed to cancel order group", err, nil)
+		return
+	}
+	response.SuccessResponse(c, "order group cancelled")
+}
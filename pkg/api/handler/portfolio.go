@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/middleware"
+)
+
+// Positions godoc
+// @Summary List open positions
+// @Description List the open positions of the authenticated user built from the fills of their orders, with the average entry price
+// @Description and the unrealized PnL at the current price, marked at the bid.
+// @Tags portfolio
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Success 200 {object} response.Response "Positions retrieved successfully"
+// @Failure 400 {object} response.Response "User ID not found in context"
+// @Failure 500 {object} response.Response "Failed to retrieve positions"
+// @Router /api/positions [get]
+func (h *UserHandler) Positions(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	data, err := h.portfolioUseCase.GetPositions(c, uint(uid))
+	if err != nil {
+		response.ErrorResponse(c, "Failed to retrieve positions", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Positions retrieved successfully", data)
+}
+
+// EquityCurve godoc
+// @Summary Get the equity curve
+// @Description Equity of the authenticated user over time from the snapshots taken by the equity snapshot job, oldest first.
+// @Description Equity is the cash (USDT) plus the other assets valued at their bid when the snapshot was taken.
+// @Description With an interval only the last snapshot of each interval from the start of the range is returned.
+// @Tags portfolio
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Param from query string false "Start of the range (RFC 3339), 30 days before the end by default"
+// @Param to query string false "End of the range (RFC 3339), now by default"
+// @Param interval query string false "Spacing of the points, e.g. 1h or 24h"
+// @Success 200 {object} response.Response "Equity curve retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid range or interval"
+// @Failure 500 {object} response.Response "Failed to retrieve the equity curve"
+// @Router /api/portfolio/equity [get]
+func (h *UserHandler) EquityCurve(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	var body request.EquityCurveRequest
+	if err := c.ShouldBindQuery(&body); err != nil {
+		response.ErrorResponse(c, "Failed to bind query", err, nil)
+		return
+	}
+
+	data, err := h.portfolioUseCase.GetEquityCurve(c, uint(uid), body)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to retrieve the equity curve", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Equity curve retrieved successfully", data)
+}
+
+// PortfolioStats godoc
+// @Summary Get the portfolio stats
+// @Description Performance of the authenticated user over the window. Total and annualized return, volatility, Sharpe and Sortino
+// @Description ratios, max drawdown and its duration come from the equity snapshots, the ratios annualized by their spacing.
+// @Description Win rate, average win and loss, profit factor and realized PnL come from the trades which closed lots in the window.
+// @Description A stat the window has too little data for is left out.
+// @Tags portfolio
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Param from query string false "Start of the window (RFC 3339), 30 days before the end by default"
+// @Param to query string false "End of the window (RFC 3339), now by default"
+// @Param riskFreeRate query number false "Annual risk free rate for the Sharpe and Sortino ratios, e.g. 0.04"
+// @Success 200 {object} response.Response "Portfolio stats retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid window"
+// @Failure 500 {object} response.Response "Failed to compute the portfolio stats"
+// @Router /api/portfolio/stats [get]
+func (h *UserHandler) PortfolioStats(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	var body request.PortfolioStatsRequest
+	if err := c.ShouldBindQuery(&body); err != nil {
+		response.ErrorResponse(c, "Failed to bind query", err, nil)
+		return
+	}
+
+	data, err := h.portfolioUseCase.GetStats(c, uint(uid), body)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to compute the portfolio stats", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Portfolio stats retrieved successfully", data)
+}
@@ -0,0 +1,15 @@
+package request
+
+const (
+	StreamMethodSubscribe   = "SUBSCRIBE"
+	StreamMethodUnsubscribe = "UNSUBSCRIBE"
+	StreamMethodList        = "LIST_SUBSCRIPTIONS"
+)
+
+// StreamRequest is a control message sent by the client on the market data socket
+// Ex: {"method": "SUBSCRIBE", "params": ["btcusdt@ticker", "ethusdt@kline_1m"], "id": 1}
+type StreamRequest struct {
+	ID     int64    `json:"id"`
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+}
@@ -1,5 +1,7 @@
 package request
 
+import "time"
+
 type RegisterUserRequest struct {
 	Username        string `json:"username" binding:"required,alphanum,min=3,max=20"`    // Ensures username is alphanumeric and within length limits
 	Email           string `json:"email" binding:"required,email"`                       // Validates email format
@@ -13,9 +15,37 @@ type LoginRequest struct {
 }
 
 type OrderRequest struct {
-	Symbol string  `json:"symbol" binding:"required"` // Asset symbol (e.g., "BTCUSDT")
-	Volume float32 `json:"volume" binding:"required"` // Quantity to buy or sell
-	Type   string  `json:"type" binding:"required"`   // Order type: "buy" or "sell"
+	Symbol          string     `json:"symbol" binding:"required"`                                                                    // Asset symbol (e.g., "BTCUSDT")
+	Volume          float64    `json:"volume" binding:"required,gt=0"`                                                               // Quantity to buy or sell
+	Type            string     `json:"type" binding:"required"`                                                                      // Order type: "buy" or "sell"
+	Kind            string     `json:"kind" binding:"omitempty,oneof=market limit stop_market stop_limit take_profit trailing_stop"` // Order kind: "market" (default), "limit", "stop_market", "stop_limit", "take_profit" or "trailing_stop"
+	LimitPrice      float64    `json:"limitPrice" binding:"gte=0"`                                                                   // Limit price, required for limit and stop limit orders
+	TriggerPrice    float64    `json:"triggerPrice" binding:"gte=0"`                                                                 // Trigger price, required for stop and take profit orders
+	TrailingOffset  float64    `json:"trailingOffset" binding:"gte=0"`                                                               // Absolute distance of a trailing stop trigger from the best price
+	TrailingPercent float64    `json:"trailingPercent" binding:"gte=0,lt=100"`                                                       // Distance of a trailing stop trigger in percent of the best price, instead of the absolute offset
+	TimeInForce     string     `json:"timeInForce" binding:"omitempty,oneof=GTC IOC FOK GTD"`                                        // Time in force: "GTC" (default), "IOC", "FOK" or "GTD"
+	ExpiresAt       *time.Time `json:"expiresAt" binding:"required_if=TimeInForce GTD"`                                              // Expiry time of a GTD order
+}
+
+// AmendOrderRequest change a resting order, the fields not set are kept
+type AmendOrderRequest struct {
+	Volume       float64 `json:"volume" binding:"gte=0"`       // New quantity, more than the quantity already filled
+	LimitPrice   float64 `json:"limitPrice" binding:"gte=0"`   // New limit price of a limit or stop limit order
+	TriggerPrice float64 `json:"triggerPrice" binding:"gte=0"` // New trigger price of a stop or take profit order
+}
+
+// OrderGroupRequest place a bracket, an entry order with a stop and a target placed once it's filled,
+// or an OCO, a stop and a target where the fill of one cancel the other
+type OrderGroupRequest struct {
+	GroupType       string  `json:"groupType" binding:"required,oneof=bracket oco"`   // Group type: "bracket" or "oco"
+	Symbol          string  `json:"symbol" binding:"required"`                        // Asset symbol (e.g., "BTCUSDT")
+	Volume          float64 `json:"volume" binding:"required,gt=0"`                   // Quantity of every order of the group
+	Type            string  `json:"type" binding:"required,oneof=buy sell"`           // Side of the bracket entry, or of both OCO orders: "buy" or "sell"
+	EntryKind       string  `json:"entryKind" binding:"omitempty,oneof=market limit"` // Bracket entry kind: "market" (default) or "limit"
+	EntryPrice      float64 `json:"entryPrice" binding:"gte=0"`                       // Limit price of a limit bracket entry
+	TakeProfitPrice float64 `json:"takeProfitPrice" binding:"required,gt=0"`          // Limit price of the target order
+	StopPrice       float64 `json:"stopPrice" binding:"required,gt=0"`                // Trigger price of the stop order
+	StopLimitPrice  float64 `json:"stopLimitPrice" binding:"gte=0"`                   // Limit price of the stop order, a stop market order when not set
 }
 
 type MarketData struct {
@@ -25,3 +55,34 @@ type MarketData struct {
 	AskPrice string `json:"askPrice"`
 	AskSize  string `json:"askSize"`
 }
+
+// CostBasisRequest set how the trades of the user close the lots of their positions
+type CostBasisRequest struct {
+	Method string `json:"method" binding:"required,oneof=FIFO LIFO AVERAGE"` // "FIFO", "LIFO" or "AVERAGE"
+}
+
+// RiskLimitsRequest change the pre-trade risk limits of the user, the limits left out keep their value and zero
+// turn a limit off
+type RiskLimitsRequest struct {
+	MaxOrderNotional *float64 `json:"maxOrderNotional" binding:"omitempty,gte=0"` // Max value of one order in the quote asset
+	MaxPositionSize  *float64 `json:"maxPositionSize" binding:"omitempty,gte=0"`  // Max value of the position in a symbol in the quote asset
+	MaxOpenOrders    *int     `json:"maxOpenOrders" binding:"omitempty,gte=0"`    // Max orders waiting to trigger or fill
+	MaxDailyVolume   *float64 `json:"maxDailyVolume" binding:"omitempty,gte=0"`   // Max value traded in a UTC day in the quote asset
+	PriceBand        *float64 `json:"priceBand" binding:"omitempty,gte=0"`        // Max distance of a limit price from the mark price, e.g. 0.05 for 5%
+	MaxDailyLoss     *float64 `json:"maxDailyLoss" binding:"omitempty,gte=0"`     // Realized and unrealized loss of a UTC day that stop the user out
+	FlattenOnStopOut *bool    `json:"flattenOnStopOut"`                           // Close the positions when the user is stopped out
+}
+
+// EquityCurveRequest select the equity snapshots of a time range, the last one of each interval when given
+type EquityCurveRequest struct {
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // Start of the range (RFC 3339), 30 days before the end by default
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // End of the range (RFC 3339), now by default
+	Interval string    `form:"interval"`                                     // Spacing of the points, e.g. "1h" or "24h", every snapshot by default
+}
+
+// PortfolioStatsRequest select the window the portfolio stats are computed over
+type PortfolioStatsRequest struct {
+	From         time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // Start of the window (RFC 3339), 30 days before the end by default
+	To           time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // End of the window (RFC 3339), now by default
+	RiskFreeRate float64   `form:"riskFreeRate" binding:"gte=0"`                 // Annual risk free rate for the Sharpe and Sortino ratios, e.g. 0.04
+}
@@ -0,0 +1,41 @@
+package response
+
+import "encoding/json"
+
+// error codes of the market data socket
+const (
+	StreamErrorInvalidRequest = iota + 1
+	StreamErrorInvalidStream
+	StreamErrorUnsupportedSymbol
+	StreamErrorTooManySubscriptions
+	StreamErrorSubscribeFailed
+)
+
+// StreamResponse acknowledge a control message of the market data socket
+type StreamResponse struct {
+	ID     int64       `json:"id"`
+	Result interface{} `json:"result"`
+}
+
+// StreamErrorResponse reject a control message of the market data socket
+type StreamErrorResponse struct {
+	ID    int64       `json:"id"`
+	Error StreamError `json:"error"`
+}
+
+type StreamError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// StreamData is a message of one of the subscribed streams
+type StreamData struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// StreamStatus tell the client a subscribed stream is stale and reconnecting, or resumed
+type StreamStatus struct {
+	Stream string `json:"stream"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,57 @@
+package response
+
+import "time"
+
+// Position is an open position valued at the price it could be closed at now
+type Position struct {
+	Symbol     string  `json:"symbol"`
+	Volume     float64 `json:"volume"`
+	EntryPrice float64 `json:"entryPrice"`
+	MarkPrice  float64 `json:"markPrice"`
+	// value of the position at the mark price
+	MarketValue          float64 `json:"marketValue"`
+	UnrealizedPnl        float64 `json:"unrealizedPnl"`
+	UnrealizedPnlPercent float64 `json:"unrealizedPnlPercent"`
+	// profit of the lots the position closed so far
+	RealizedPnl float64 `json:"realizedPnl"`
+	// false when the symbol has no quote right now, the mark price and PnL are then left empty
+	Priced bool `json:"priced"`
+}
+
+// EquityPoint is the equity of the accounts at a time, cash in the quote asset and the other assets at their bid
+type EquityPoint struct {
+	Time           time.Time `json:"time"`
+	Cash           float64   `json:"cash"`
+	PositionsValue float64   `json:"positionsValue"`
+	Equity         float64   `json:"equity"`
+}
+
+// PortfolioStats is the performance of the portfolio over a window, returns and ratios come from the
+// equity snapshots and the trade stats from the trades which closed lots. a stat the window has too
+// little data for is left out.
+type PortfolioStats struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	StartEquity float64 `json:"startEquity"`
+	EndEquity   float64 `json:"endEquity"`
+	Snapshots   int     `json:"snapshots"`
+
+	TotalReturn      *float64 `json:"totalReturn,omitempty"`
+	AnnualizedReturn *float64 `json:"annualizedReturn,omitempty"`
+	Volatility       *float64 `json:"volatility,omitempty"`
+	SharpeRatio      *float64 `json:"sharpeRatio,omitempty"`
+	SortinoRatio     *float64 `json:"sortinoRatio,omitempty"`
+	// largest fall from a peak as a fraction of the peak, and the longest time spent below a peak
+	MaxDrawdown              float64 `json:"maxDrawdown"`
+	MaxDrawdownDurationHours float64 `json:"maxDrawdownDurationHours"`
+
+	ClosingTrades int      `json:"closingTrades"`
+	Wins          int      `json:"wins"`
+	Losses        int      `json:"losses"`
+	WinRate       *float64 `json:"winRate,omitempty"`
+	AverageWin    *float64 `json:"averageWin,omitempty"`
+	AverageLoss   *float64 `json:"averageLoss,omitempty"`
+	ProfitFactor  *float64 `json:"profitFactor,omitempty"`
+	RealizedPnl   float64  `json:"realizedPnl"`
+}
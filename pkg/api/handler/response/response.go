@@ -1,6 +1,7 @@
 package response
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -27,7 +28,14 @@ func SuccessResponse(ctx *gin.Context, message string, data ...interface{}) {
 	ctx.JSON(http.StatusOK, response)
 }
 func ErrorResponse(ctx *gin.Context, message string, err error, data interface{}) {
-	errFields := strings.Split(err.Error(), "\n")
+	var errFields interface{} = strings.Split(err.Error(), "\n")
+
+	// the rules a refused order broke are listed with their codes
+	var riskErr *RiskError
+	if errors.As(err, &riskErr) {
+		errFields = riskErr.Violations
+	}
+
 	response := Response{
 		Success: false,
 		Message: message,
@@ -49,6 +57,8 @@ func getErrorCode(err error) int {
 		httpCode = http.StatusInternalServerError
 	case codes.Unauthenticated:
 		httpCode = http.StatusUnauthorized
+	case codes.FailedPrecondition:
+		httpCode = http.StatusUnprocessableEntity
 	default:
 		httpCode = http.StatusBadRequest
 	}
@@ -0,0 +1,47 @@
+package response
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RiskLimits is the pre-trade risk limits the orders of a user are checked against, zero is not enforced
+type RiskLimits struct {
+	MaxOrderNotional float64 `json:"maxOrderNotional"`
+	MaxPositionSize  float64 `json:"maxPositionSize"`
+	MaxOpenOrders    int     `json:"maxOpenOrders"`
+	MaxDailyVolume   float64 `json:"maxDailyVolume"`
+	PriceBand        float64 `json:"priceBand"`
+	MaxDailyLoss     float64 `json:"maxDailyLoss"`
+	FlattenOnStopOut bool    `json:"flattenOnStopOut"`
+	// false while the user use the default limits
+	Custom bool `json:"custom"`
+}
+
+// RiskViolation is a risk rule an order broke, the value the order would reach and the limit of the rule
+type RiskViolation struct {
+	Rule    string  `json:"rule"`
+	Message string  `json:"message"`
+	Limit   float64 `json:"limit"`
+	Value   float64 `json:"value"`
+}
+
+// RiskError refuse an order breaking risk rules, the error of the response list the violations
+type RiskError struct {
+	Violations []RiskViolation
+}
+
+func (e *RiskError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return strings.Join(messages, "\n")
+}
+
+// GRPCStatus make the refusal a failed precondition
+func (e *RiskError) GRPCStatus() *status.Status {
+	return status.New(codes.FailedPrecondition, e.Error())
+}
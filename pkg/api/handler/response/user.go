@@ -1,17 +1,36 @@
 package response
 
+import "time"
+
 type Token struct {
 	AccessToken string
 }
 
 type OrderResponse struct {
-	OrderID   uint    `json:"orderId"`
-	OrderUUID string  `json:"orderUUID"`
-	Symbol    string  `json:"symbol"`
-	Volume    float32 `json:"volume"`
-	Price     float64 `json:"price"`
-	Type      string  `json:"type"`
-	Status    string  `json:"status"`
+	OrderID         uint       `json:"orderId"`
+	OrderUUID       string     `json:"orderUUID"`
+	Symbol          string     `json:"symbol"`
+	Volume          float64    `json:"volume"`
+	Price           float64    `json:"price"`
+	Type            string     `json:"type"`
+	Kind            string     `json:"kind"`
+	LimitPrice      float64    `json:"limitPrice,omitempty"`
+	TriggerPrice    float64    `json:"triggerPrice,omitempty"`
+	TrailingOffset  float64    `json:"trailingOffset,omitempty"`
+	TrailingPercent float64    `json:"trailingPercent,omitempty"`
+	BestPrice       float64    `json:"bestPrice,omitempty"`
+	FilledVolume    float64    `json:"filledVolume"`
+	Reserved        float64    `json:"reserved,omitempty"`
+	Fee             float64    `json:"fee"`
+	Status          string     `json:"status"`
+	TimeInForce     string     `json:"timeInForce"`
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	EndReason       string     `json:"endReason,omitempty"`
+	ParentID        *uint      `json:"parentOrderId,omitempty"`
+	GroupID         *uint      `json:"groupId,omitempty"`
+	GroupLeg        string     `json:"groupLeg,omitempty"`
+	TriggeredAt     *time.Time `json:"triggeredAt,omitempty"`
+	FilledAt        *time.Time `json:"filledAt,omitempty"`
 }
 
 type MarketData struct {
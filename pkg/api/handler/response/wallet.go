@@ -0,0 +1,21 @@
+package response
+
+// Wallet is the holdings of a user valued in the quote asset at the current bid
+type Wallet struct {
+	ValueAsset string          `json:"valueAsset"`
+	TotalValue float64         `json:"totalValue"`
+	Assets     []WalletBalance `json:"assets"`
+	// how the trades close the lots of the positions, FIFO, LIFO or AVERAGE
+	CostBasisMethod string `json:"costBasisMethod"`
+}
+
+type WalletBalance struct {
+	Asset  string  `json:"asset"`
+	Free   float64 `json:"free"`
+	Locked float64 `json:"locked"`
+	Total  float64 `json:"total"`
+	Price  float64 `json:"price"`
+	Value  float64 `json:"value"`
+	// false when the asset has no market price right now, its value is then left out of the total
+	Priced bool `json:"priced"`
+}
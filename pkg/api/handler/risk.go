@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/middleware"
+)
+
+// RiskLimits godoc
+// @Summary Get the risk limits
+// @Description Get the pre-trade risk limits the orders of the authenticated user are checked against, "custom" is false
+// @Description while the user has the default limits. A limit of zero is not enforced. Every order, order group and amendment
+// @Description go through the rules before anything is saved: "max_order_notional" cap the value of the order,
+// @Description "max_position_size" the value of the position in the symbol once a buy is filled, "max_open_orders" the orders
+// @Description waiting to trigger or fill, "max_daily_volume" the value traded since the start of the UTC day with what the
+// @Description order has left to fill, and "price_band" how far a limit price may be from the mark price, as a fraction of it.
+// @Description An order breaking rules is refused with 422 and the error list every rule it broke with its limit and the value
+// @Description the order reached. "maxDailyLoss" is the realized and unrealized loss of a UTC day that stop the user out
+// @Description (see /api/risk/stop-outs), their positions are closed too when "flattenOnStopOut" is set.
+// @Description The limits are set by an admin.
+// @Tags risk
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Success 200 {object} response.Response "Risk limits retrieved successfully"
+// @Failure 400 {object} response.Response "User ID not found in context"
+// @Failure 500 {object} response.Response "Failed to retrieve risk limits"
+// @Router /api/risk/limits [get]
+func (h *UserHandler) RiskLimits(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	data, err := h.riskUseCase.GetRiskLimits(c, uint(uid))
+	if err != nil {
+		response.ErrorResponse(c, "Failed to retrieve risk limits", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Risk limits retrieved successfully", data)
+}
+
+// SetRiskLimits godoc
+// @Summary Set the risk limits of a user
+// @Description Change the pre-trade risk limits of the user (see /api/risk/limits), the limits left out keep their value
+// @Description and zero turn a limit off. Requires the admin key in the X-Admin-Key header.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param id path int true "User ID"
+// @Param riskLimitsRequest body request.RiskLimitsRequest true "Risk limits"
+// @Success 200 {object} response.Response "Risk limits updated"
+// @Failure 400 {object} response.Response "Invalid risk limits"
+// @Failure 401 {object} response.Response "Invalid admin key"
+// @Failure 500 {object} response.Response "Failed to update the risk limits"
+// @Router /api/admin/users/{id}/risk-limits [put]
+func (h *UserHandler) SetRiskLimits(c *gin.Context) {
+
+	uid, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.ErrorResponse(c, "Invalid user id", err, nil)
+		return
+	}
+
+	var body request.RiskLimitsRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.ErrorResponse(c, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	data, err := h.riskUseCase.SetRiskLimits(c, uint(uid), body)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to update the risk limits", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Risk limits updated", data)
+}
+
+// StopOuts godoc
+// @Summary List the stop outs
+// @Description List the kill switches the daily loss of the authenticated user tripped, newest first. The loss is the realized PnL
+// @Description of the trades of the UTC day and the unrealized PnL, the change of the equity since its first snapshot of the day.
+// @Description A stop out cancel the live orders, close the positions when "flattenOnStopOut" is set, and refuse new orders
+// @Description with 422 "daily_loss_limit" until the next UTC day or an admin reset ("resetAt").
+// @Tags risk
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Success 200 {object} response.Response "Stop outs retrieved successfully"
+// @Failure 400 {object} response.Response "User ID not found in context"
+// @Failure 500 {object} response.Response "Failed to retrieve stop outs"
+// @Router /api/risk/stop-outs [get]
+func (h *UserHandler) StopOuts(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	data, err := h.riskUseCase.ListStopOuts(c, uint(uid))
+	if err != nil {
+		response.ErrorResponse(c, "Failed to retrieve stop outs", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Stop outs retrieved successfully", data)
+}
+
+// ResetStopOut godoc
+// @Summary Reset the stop out of a user
+// @Description Lift the kill switch the user tripped today, they can place orders again for the rest of the UTC day
+// @Description and are not stopped out again before the next one. The user is sent a "stop_out_reset" event.
+// @Description Requires the admin key in the X-Admin-Key header.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param id path int true "User ID"
+// @Success 200 {object} response.Response "Stop out reset"
+// @Failure 401 {object} response.Response "Invalid admin key"
+// @Failure 422 {object} response.Response "User is not stopped out"
+// @Router /api/admin/users/{id}/stop-out/reset [post]
+func (h *UserHandler) ResetStopOut(c *gin.Context) {
+
+	uid, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.ErrorResponse(c, "Invalid user id", err, nil)
+		return
+	}
+
+	if err := h.riskUseCase.ResetStopOut(c, uint(uid)); err != nil {
+		response.ErrorResponse(c, "Failed to reset the stop out", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Stop out reset", nil)
+}
+
+// AccountEvents godoc
+// @Summary Account event stream
+// @Description Opens a WebSocket connection pushing the events of the authenticated user as {"type": "...", "data": {...}, "time": "..."}.
+// @Description "stop_out" is sent when the daily loss trip the kill switch of the user and "stop_out_reset" when an admin
+// @Description lift it, both with the stop out as data.
+// @Tags risk
+// @Security BearerTokenAuth
+// @Produce json
+// @Router /api/account/events [get]
+func (h *UserHandler) AccountEvents(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	clientWS, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade client connection: %v", err)
+		return
+	}
+	defer clientWS.Close()
+
+	events, cancel := h.notifier.Subscribe(uint(uid))
+	defer cancel()
+
+	// the socket is only written, reading notice the client going away
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := clientWS.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for event := range events {
+		if err := clientWS.WriteJSON(event); err != nil {
+			log.Printf("Failed to send event to client: %v", err)
+			return
+		}
+	}
+}
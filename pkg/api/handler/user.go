@@ -1,13 +1,12 @@
 package handler
 
 import (
-	"sync"
-
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla/websocket"
 	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/interfaces"
 	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
 	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/notify"
 	"github.com/kannan112/mock-trading-platform-api/pkg/service/token"
 	usecaseInterface "github.com/kannan112/mock-trading-platform-api/pkg/usecase/interfaces"
 )
@@ -17,15 +16,29 @@ const (
 )
 
 type UserHandler struct {
-	userUseCase usecaseInterface.UserUseCase
-	clients     map[*websocket.Conn]bool
-	clientsMux  sync.RWMutex
+	userUseCase      usecaseInterface.UserUseCase
+	orderUseCase     usecaseInterface.OrderUseCase
+	walletUseCase    usecaseInterface.WalletUseCase
+	portfolioUseCase usecaseInterface.PortfolioUseCase
+	symbolUseCase    usecaseInterface.SymbolUseCase
+	riskUseCase      usecaseInterface.RiskUseCase
+	marketHub        marketdata.Hub
+	notifier         notify.Notifier
 }
 
-func NewUserHandler(userUsecase usecaseInterface.UserUseCase, tokenService token.TokenService) interfaces.UserHandler {
+func NewUserHandler(userUsecase usecaseInterface.UserUseCase, orderUseCase usecaseInterface.OrderUseCase,
+	walletUseCase usecaseInterface.WalletUseCase, portfolioUseCase usecaseInterface.PortfolioUseCase,
+	symbolUseCase usecaseInterface.SymbolUseCase, riskUseCase usecaseInterface.RiskUseCase,
+	tokenService token.TokenService, marketHub marketdata.Hub, notifier notify.Notifier) interfaces.UserHandler {
 	return &UserHandler{
-		userUseCase: userUsecase,
-		clients:     make(map[*websocket.Conn]bool),
+		userUseCase:      userUsecase,
+		orderUseCase:     orderUseCase,
+		walletUseCase:    walletUseCase,
+		portfolioUseCase: portfolioUseCase,
+		symbolUseCase:    symbolUseCase,
+		riskUseCase:      riskUseCase,
+		marketHub:        marketHub,
+		notifier:         notifier,
 	}
 }
 
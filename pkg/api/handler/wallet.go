@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/middleware"
+)
+
+// Wallet godoc
+// @Summary Get the wallet
+// @Description List the assets held by the authenticated user with their free balance and the balance locked by live orders.
+// @Description Each asset is valued in USDT at the current bid of its USDT market, an asset without a market price is listed
+// @Description with "priced": false and left out of the total value.
+// @Tags wallet
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Success 200 {object} response.Response "Wallet retrieved successfully"
+// @Failure 400 {object} response.Response "User ID not found in context"
+// @Failure 500 {object} response.Response "Failed to retrieve wallet"
+// @Router /api/wallet [get]
+func (h *UserHandler) Wallet(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	data, err := h.walletUseCase.GetWallet(c, uint(uid))
+	if err != nil {
+		response.ErrorResponse(c, "Failed to retrieve wallet", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Wallet retrieved successfully", data)
+}
+
+// Ledger godoc
+// @Summary Get the ledger
+// @Description List the ledger entries behind the wallet balances of the authenticated user, newest first.
+// @Description Every deposit, reservation, release and fill post a journal of balanced entries, a debit add to the
+// @Description "available" or "locked" balance of the asset and a credit take from it. Entries reference the order they came from.
+// @Tags wallet
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Param asset query string false "Only the entries of this asset (e.g., USDT)"
+// @Success 200 {object} response.Response "Ledger retrieved successfully"
+// @Failure 400 {object} response.Response "User ID not found in context"
+// @Failure 500 {object} response.Response "Failed to retrieve ledger"
+// @Router /api/wallet/ledger [get]
+func (h *UserHandler) Ledger(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	data, err := h.walletUseCase.GetLedger(c, uint(uid), c.Query("asset"))
+	if err != nil {
+		response.ErrorResponse(c, "Failed to retrieve ledger", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Ledger retrieved successfully", data)
+}
+
+// SetCostBasis godoc
+// @Summary Set the cost basis method
+// @Description Set how the trades of the authenticated user close the tax lots of their positions and realize PnL.
+// @Description "FIFO" (default) close the oldest lots first, "LIFO" the newest lots first and "AVERAGE" every lot in proportion
+// @Description at their weighted average price. The lots already closed keep their matches.
+// @Tags wallet
+// @Accept json
+// @Security BearerTokenAuth
+// @Produce json
+// @Param costBasisRequest body request.CostBasisRequest true "Cost basis method"
+// @Success 200 {object} response.Response "Cost basis method updated"
+// @Failure 400 {object} response.Response "Invalid cost basis method"
+// @Failure 500 {object} response.Response "Failed to update the cost basis method"
+// @Router /api/wallet/cost-basis [put]
+func (h *UserHandler) SetCostBasis(c *gin.Context) {
+
+	uid, err := middleware.GetUserIdFromContext(c)
+	if err != nil {
+		response.ErrorResponse(c, "Failed to get user id from context", err, nil)
+		return
+	}
+
+	var body request.CostBasisRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.ErrorResponse(c, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	if err := h.walletUseCase.SetCostBasisMethod(c, uint(uid), body.Method); err != nil {
+		response.ErrorResponse(c, "Failed to update the cost basis method", err, nil)
+		return
+	}
+
+	response.SuccessResponse(c, "Cost basis method updated", nil)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth let through the requests with the admin key in the X-Admin-Key header,
+// every request is refused when there is no key
+func AdminAuth(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("X-Admin-Key")
+		if key == "" || subtle.ConstantTimeCompare([]byte(header), []byte(key)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid admin key"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
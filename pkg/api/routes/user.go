@@ -8,7 +8,7 @@ import (
 
 func UserRoutes(api *gin.RouterGroup,
 	userHandler handlerInterface.UserHandler,
-
+	adminKey string,
 ) {
 
 	auth := api.Group("/auth")
@@ -19,7 +19,9 @@ func UserRoutes(api *gin.RouterGroup,
 	}
 	{
 		api.GET("/market-data", userHandler.StreamMarketData)
+		api.GET("/market-data/stats", userHandler.MarketDataStats)
 		api.GET("/market-live", userHandler.WebSocketTestPage)
+		api.GET("/symbols", userHandler.ListSymbols)
 	}
 
 	{
@@ -27,11 +29,70 @@ func UserRoutes(api *gin.RouterGroup,
 		order.Use(middleware.UserAuth)
 		{
 			order.POST("", userHandler.OrderHandler)
-			order.DELETE(":id", userHandler.DeteleTrade)
+			order.DELETE(":id", userHandler.CancelOrder)
+			order.PATCH(":id", userHandler.AmendOrder)
 			order.GET(":id", userHandler.OrderDetails)
 			order.GET("/trade-history", userHandler.AllOrders)
+
+			order.POST("/group", userHandler.PlaceOrderGroup)
+			order.GET("/groups", userHandler.ListOrderGroups)
+			order.GET("/group/:id", userHandler.OrderGroupDetails)
+			order.DELETE("/group/:id", userHandler.CancelOrderGroup)
+		}
+
+	}
+
+	{
+		wallet := api.Group("/wallet")
+		wallet.Use(middleware.UserAuth)
+		{
+			wallet.GET("", userHandler.Wallet)
+			wallet.GET("/ledger", userHandler.Ledger)
+			wallet.PUT("/cost-basis", userHandler.SetCostBasis)
+		}
+	}
+
+	{
+		risk := api.Group("/risk")
+		risk.Use(middleware.UserAuth)
+		{
+			risk.GET("/limits", userHandler.RiskLimits)
+			risk.GET("/stop-outs", userHandler.StopOuts)
+		}
+	}
+
+	{
+		account := api.Group("/account")
+		account.Use(middleware.UserAuth)
+		{
+			account.GET("/events", userHandler.AccountEvents)
+		}
+	}
+
+	{
+		admin := api.Group("/admin")
+		admin.Use(middleware.AdminAuth(adminKey))
+		{
+			admin.PUT("/users/:id/risk-limits", userHandler.SetRiskLimits)
+			admin.POST("/users/:id/stop-out/reset", userHandler.ResetStopOut)
+		}
+	}
+
+	{
+		positions := api.Group("/positions")
+		positions.Use(middleware.UserAuth)
+		{
+			positions.GET("", userHandler.Positions)
 		}
+	}
 
+	{
+		portfolio := api.Group("/portfolio")
+		portfolio.Use(middleware.UserAuth)
+		{
+			portfolio.GET("/equity", userHandler.EquityCurve)
+			portfolio.GET("/stats", userHandler.PortfolioStats)
+		}
 	}
 
 }
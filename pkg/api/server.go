@@ -1,19 +1,31 @@
 package http
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/kannan112/mock-trading-platform-api/cmd/api/docs"
 	handlerInterface "github.com/kannan112/mock-trading-platform-api/pkg/api/handler/interfaces"
 	"github.com/kannan112/mock-trading-platform-api/pkg/api/routes"
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+	"github.com/kannan112/mock-trading-platform-api/pkg/worker"
 
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// time the requests in flight get to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
 type ServerHTTP struct {
 	Engine *gin.Engine
+	worker *worker.Worker
 }
 
 // @title					Trading Platform Backend API
@@ -28,7 +40,7 @@ type ServerHTTP struct {
 // @Description				Add prefix of Bearer before  token Ex: "Bearer token"
 // @Query.collection.format	multi
 
-func NewServerHTTP(userHandler handlerInterface.UserHandler) *ServerHTTP {
+func NewServerHTTP(cfg config.Config, userHandler handlerInterface.UserHandler, worker *worker.Worker) *ServerHTTP {
 
 	engine := gin.New()
 
@@ -40,7 +52,7 @@ func NewServerHTTP(userHandler handlerInterface.UserHandler) *ServerHTTP {
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
 	// set up routes
-	routes.UserRoutes(engine.Group("/api"), userHandler)
+	routes.UserRoutes(engine.Group("/api"), userHandler, cfg.AdminAPIKey)
 
 	// no handler
 	engine.NoRoute(func(ctx *gin.Context) {
@@ -49,10 +61,32 @@ func NewServerHTTP(userHandler handlerInterface.UserHandler) *ServerHTTP {
 		})
 	})
 
-	return &ServerHTTP{Engine: engine}
+	return &ServerHTTP{Engine: engine, worker: worker}
 }
 
+// Start serve the api until the process is interrupted, then stop the server and the background jobs
 func (s *ServerHTTP) Start() error {
 
-	return s.Engine.Run(":8080")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// background jobs run as long as the server
+	s.worker.Start(ctx)
+
+	server := &http.Server{Addr: ":8080", Handler: s.Engine}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down the server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 )
@@ -11,15 +13,186 @@ type Config struct {
 	DBUser     string `mapstructure:"DB_USER"`
 	DBPort     string `mapstructure:"DB_PORT"`
 	DBPassword string `mapstructure:"DB_PASSWORD"`
+
+	// market data feed used for quotes and streams (binance, synthetic or replay)
+	MarketDataProvider string `mapstructure:"MARKET_DATA_PROVIDER" validate:"oneof=binance synthetic replay"`
+	BinanceRestURL     string `mapstructure:"BINANCE_REST_URL"`
+	BinanceWsURL       string `mapstructure:"BINANCE_WS_URL"`
+
+	// offline price generator, drift and volatility are annualized and spread is a fraction of the mid price
+	SyntheticSeed         int64         `mapstructure:"SYNTHETIC_SEED"`
+	SyntheticDrift        float64       `mapstructure:"SYNTHETIC_DRIFT"`
+	SyntheticVolatility   float64       `mapstructure:"SYNTHETIC_VOLATILITY" validate:"gte=0"`
+	SyntheticSpread       float64       `mapstructure:"SYNTHETIC_SPREAD" validate:"gte=0,lt=1"`
+	SyntheticStartPrices  string        `mapstructure:"SYNTHETIC_START_PRICES"`
+	SyntheticTickInterval time.Duration `mapstructure:"SYNTHETIC_TICK_INTERVAL" validate:"gt=0"`
+
+	// record the streamed market data to a JSON lines file, and replay such a file at the given speed
+	MarketDataRecordFile  string  `mapstructure:"MARKET_DATA_RECORD_FILE"`
+	MarketDataReplayFile  string  `mapstructure:"MARKET_DATA_REPLAY_FILE" validate:"required_if=MarketDataProvider replay"`
+	MarketDataReplaySpeed float64 `mapstructure:"MARKET_DATA_REPLAY_SPEED" validate:"gt=0"`
+
+	// exchangeInfo style JSON file the symbol registry is seeded from instead of the feed,
+	// and how often the registry is loaded again
+	SymbolSeedFile        string        `mapstructure:"SYMBOL_SEED_FILE"`
+	SymbolRefreshInterval time.Duration `mapstructure:"SYMBOL_REFRESH_INTERVAL" validate:"gt=0"`
+
+	// levels of the order book the orders are filled across, and the step between the levels of the
+	// depth curve laid around the quote by the synthetic and replay feeds, a fraction of the price
+	MarketDepthLevels int     `mapstructure:"MARKET_DEPTH_LEVELS" validate:"oneof=5 10 20 50 100 500 1000 5000"`
+	MarketDepthStep   float64 `mapstructure:"MARKET_DEPTH_STEP" validate:"gt=0,lt=1"`
+
+	// messages buffered for each market data socket, and what to do with a client whose buffer is full
+	MarketHubClientBuffer int    `mapstructure:"MARKET_HUB_CLIENT_BUFFER" validate:"gt=0"`
+	MarketHubSlowClient   string `mapstructure:"MARKET_HUB_SLOW_CLIENT" validate:"oneof=drop disconnect"`
+
+	// jittered exponential backoff between reconnects of a failed provider stream
+	MarketReconnectMinBackoff time.Duration `mapstructure:"MARKET_RECONNECT_MIN_BACKOFF" validate:"gt=0"`
+	MarketReconnectMaxBackoff time.Duration `mapstructure:"MARKET_RECONNECT_MAX_BACKOFF" validate:"gtefield=MarketReconnectMinBackoff"`
+
+	// how often the resting orders are matched against the market price
+	// and the good till date orders past their expiry are expired
+	OrderMatchInterval  time.Duration `mapstructure:"ORDER_MATCH_INTERVAL" validate:"gt=0"`
+	OrderExpiryInterval time.Duration `mapstructure:"ORDER_EXPIRY_INTERVAL" validate:"gt=0"`
+
+	// fill a market order partially when the order book run out and cancel the rest,
+	// otherwise the order is rejected
+	OrderPartialFills bool `mapstructure:"ORDER_PARTIAL_FILLS"`
+
+	// simulated exchange, a new order wait a random latency between the min and max before it's priced and
+	// filled, and is rejected at the reject rate. a limit order touched but not traded through fill only part
+	// of the book at the touch partial rate, drawn again on every matching pass while the book stay at its price.
+	// the draws are seeded so simulated runs are reproducible.
+	ExecutionSeed             int64         `mapstructure:"EXECUTION_SEED"`
+	ExecutionLatencyMin       time.Duration `mapstructure:"EXECUTION_LATENCY_MIN" validate:"gte=0"`
+	ExecutionLatencyMax       time.Duration `mapstructure:"EXECUTION_LATENCY_MAX" validate:"gtefield=ExecutionLatencyMin"`
+	ExecutionRejectRate       float64       `mapstructure:"EXECUTION_REJECT_RATE" validate:"gte=0,lte=1"`
+	ExecutionTouchPartialRate float64       `mapstructure:"EXECUTION_TOUCH_PARTIAL_RATE" validate:"gte=0,lte=1"`
+
+	// how often the account balances are reconciled with the ledger
+	LedgerReconcileInterval time.Duration `mapstructure:"LEDGER_RECONCILE_INTERVAL" validate:"gt=0"`
+
+	// how often the equity of every user is saved for the equity curve
+	EquitySnapshotInterval time.Duration `mapstructure:"EQUITY_SNAPSHOT_INTERVAL" validate:"gt=0"`
+
+	// USDT balance credited to a new user
+	AccountStartingBalance float64 `mapstructure:"ACCOUNT_STARTING_BALANCE" validate:"gte=0"`
+
+	// fee rates of the fills, a fraction of the trade value charged in the quote asset. the tiers are a
+	// comma separated list of MIN_VALUE:MAKER:TAKER lowering the rates once a user traded MIN_VALUE in 30 days
+	FeeMakerRate float64 `mapstructure:"FEE_MAKER_RATE" validate:"gte=0,lt=1"`
+	FeeTakerRate float64 `mapstructure:"FEE_TAKER_RATE" validate:"gte=0,lt=1"`
+	FeeTiers     string  `mapstructure:"FEE_TIERS"`
+
+	// default pre-trade risk limits of the users without their own, zero is not enforced. notionals, position
+	// size and daily volume are values in the quote asset, the price band is the fraction of the mark price
+	// a limit price may be away from it
+	RiskMaxOrderNotional float64 `mapstructure:"RISK_MAX_ORDER_NOTIONAL" validate:"gte=0"`
+	RiskMaxPositionSize  float64 `mapstructure:"RISK_MAX_POSITION_SIZE" validate:"gte=0"`
+	RiskMaxOpenOrders    int     `mapstructure:"RISK_MAX_OPEN_ORDERS" validate:"gte=0"`
+	RiskMaxDailyVolume   float64 `mapstructure:"RISK_MAX_DAILY_VOLUME" validate:"gte=0"`
+	RiskPriceBand        float64 `mapstructure:"RISK_PRICE_BAND" validate:"gte=0"`
+
+	// default daily loss limit, realized and unrealized in the quote asset. once breached the live orders of
+	// the user are cancelled, their positions closed when flatten is set, and new orders refused until the next
+	// UTC day or an admin reset. the loss of every trading user is checked each interval
+	RiskMaxDailyLoss      float64       `mapstructure:"RISK_MAX_DAILY_LOSS" validate:"gte=0"`
+	RiskFlattenOnStopOut  bool          `mapstructure:"RISK_FLATTEN_ON_STOP_OUT"`
+	RiskLossCheckInterval time.Duration `mapstructure:"RISK_LOSS_CHECK_INTERVAL" validate:"gt=0"`
+
+	// key of the admin endpoints, sent in the X-Admin-Key header. they are closed without one
+	AdminAPIKey string `mapstructure:"ADMIN_API_KEY"`
 }
 
 // name of envs and used to read from system envs
 var envsNames = []string{
 	"DB_HOST", "DB_NAME", "DB_USER", "DB_PORT", "DB_PASSWORD",
+	"MARKET_DATA_PROVIDER", "BINANCE_REST_URL", "BINANCE_WS_URL",
+	"SYNTHETIC_SEED", "SYNTHETIC_DRIFT", "SYNTHETIC_VOLATILITY", "SYNTHETIC_SPREAD",
+	"SYNTHETIC_START_PRICES", "SYNTHETIC_TICK_INTERVAL",
+	"MARKET_DATA_RECORD_FILE", "MARKET_DATA_REPLAY_FILE", "MARKET_DATA_REPLAY_SPEED",
+	"SYMBOL_SEED_FILE", "SYMBOL_REFRESH_INTERVAL",
+	"MARKET_DEPTH_LEVELS", "MARKET_DEPTH_STEP",
+	"MARKET_HUB_CLIENT_BUFFER", "MARKET_HUB_SLOW_CLIENT",
+	"MARKET_RECONNECT_MIN_BACKOFF", "MARKET_RECONNECT_MAX_BACKOFF",
+	"ORDER_MATCH_INTERVAL",
+	"ORDER_EXPIRY_INTERVAL",
+	"ORDER_PARTIAL_FILLS",
+	"EXECUTION_SEED", "EXECUTION_LATENCY_MIN", "EXECUTION_LATENCY_MAX",
+	"EXECUTION_REJECT_RATE", "EXECUTION_TOUCH_PARTIAL_RATE",
+	"ACCOUNT_STARTING_BALANCE",
+	"LEDGER_RECONCILE_INTERVAL",
+	"EQUITY_SNAPSHOT_INTERVAL",
+	"FEE_MAKER_RATE", "FEE_TAKER_RATE", "FEE_TIERS",
+	"RISK_MAX_ORDER_NOTIONAL", "RISK_MAX_POSITION_SIZE", "RISK_MAX_OPEN_ORDERS",
+	"RISK_MAX_DAILY_VOLUME", "RISK_PRICE_BAND",
+	"RISK_MAX_DAILY_LOSS", "RISK_FLATTEN_ON_STOP_OUT", "RISK_LOSS_CHECK_INTERVAL",
+	"ADMIN_API_KEY",
+}
+
+// default values for the optional envs
+var envDefaults = map[string]interface{}{
+	"MARKET_DATA_PROVIDER": "binance",
+	"BINANCE_REST_URL":     "https://api.binance.com",
+	"BINANCE_WS_URL":       "wss://stream.binance.com:9443",
+
+	"SYNTHETIC_SEED":          1,
+	"SYNTHETIC_DRIFT":         0.0,
+	"SYNTHETIC_VOLATILITY":    0.8,
+	"SYNTHETIC_SPREAD":        0.0002,
+	"SYNTHETIC_START_PRICES":  "BTCUSDT=60000,ETHUSDT=3000,BNBUSDT=550,ADAUSDT=0.45,DOGEUSDT=0.12,XRPUSDT=0.55",
+	"SYNTHETIC_TICK_INTERVAL": "1s",
+
+	"MARKET_DATA_REPLAY_SPEED": 1.0,
+
+	"SYMBOL_REFRESH_INTERVAL": "24h",
+
+	"MARKET_DEPTH_LEVELS": 20,
+	"MARKET_DEPTH_STEP":   0.0005,
+
+	"MARKET_HUB_CLIENT_BUFFER": 256,
+	"MARKET_HUB_SLOW_CLIENT":   "drop",
+
+	"MARKET_RECONNECT_MIN_BACKOFF": "500ms",
+	"MARKET_RECONNECT_MAX_BACKOFF": "30s",
+
+	"ORDER_MATCH_INTERVAL":  "1s",
+	"ORDER_EXPIRY_INTERVAL": "1s",
+	"ORDER_PARTIAL_FILLS":   true,
+
+	"EXECUTION_SEED":               1,
+	"EXECUTION_LATENCY_MIN":        "0s",
+	"EXECUTION_LATENCY_MAX":        "0s",
+	"EXECUTION_REJECT_RATE":        0.0,
+	"EXECUTION_TOUCH_PARTIAL_RATE": 0.0,
+
+	"ACCOUNT_STARTING_BALANCE": 10000.0,
+
+	"LEDGER_RECONCILE_INTERVAL": "1h",
+
+	"EQUITY_SNAPSHOT_INTERVAL": "1h",
+
+	"FEE_MAKER_RATE": 0.001,
+	"FEE_TAKER_RATE": 0.001,
+	"FEE_TIERS":      "",
+
+	"RISK_MAX_ORDER_NOTIONAL": 0.0,
+	"RISK_MAX_POSITION_SIZE":  0.0,
+	"RISK_MAX_OPEN_ORDERS":    0,
+	"RISK_MAX_DAILY_VOLUME":   0.0,
+	"RISK_PRICE_BAND":         0.0,
+
+	"RISK_MAX_DAILY_LOSS":      0.0,
+	"RISK_FLATTEN_ON_STOP_OUT": false,
+	"RISK_LOSS_CHECK_INTERVAL": "10s",
 }
 
 func LoadConfig() (config Config, err error) {
 
+	for env, value := range envDefaults {
+		viper.SetDefault(env, value)
+	}
+
 	viper.AddConfigPath("./")
 	viper.SetConfigFile(".env")
 	err = viper.ReadInConfig()
@@ -24,12 +24,20 @@ func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
 	}
 
 	// migrate the database tables
-	err = db.AutoMigrate(&domain.User{}, &domain.Account{}, &domain.Order{}, &domain.Trade{}, &domain.Position{})
+	err = db.AutoMigrate(&domain.User{}, &domain.Account{}, &domain.LedgerEntry{}, &domain.Order{}, &domain.OrderGroup{}, &domain.OrderAmendment{}, &domain.Trade{}, &domain.TaxLot{}, &domain.LotMatch{}, &domain.Position{}, &domain.EquitySnapshot{}, &domain.Symbol{}, &domain.RiskLimits{}, &domain.StopOut{})
 
 	if err != nil {
 		log.Printf("failed to migrate database models")
 		return nil, err
 	}
 
+	opened, err := openLedgerBalances(db)
+	if err != nil {
+		return nil, err
+	}
+	if opened > 0 {
+		log.Printf("posted %d opening ledger entries for the balances from before the ledger", opened)
+	}
+
 	return db, err
 }
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"gorm.io/gorm"
+)
+
+// post an opening journal for the accounts which have a balance but no ledger entries, the ones
+// funded before the ledger, so the reconciliation start from their balance. an account is opened
+// once, after that it has entries
+func openLedgerBalances(db *gorm.DB) (int64, error) {
+
+	query := `
+        WITH opening AS (
+            SELECT a.user_id, a.asset, a.balance, a.locked, gen_random_uuid()::text AS journal_id
+            FROM accounts a
+            WHERE (a.balance <> 0 OR a.locked <> 0)
+                AND NOT EXISTS (
+                    SELECT 1 FROM ledger_entries l WHERE l.user_id = a.user_id AND l.asset = a.asset
+                )
+        )
+        INSERT INTO ledger_entries (journal_id, user_id, asset, account, debit, credit, kind, created_at)
+        SELECT journal_id, user_id, asset, $1, balance, 0, $4, $5 FROM opening WHERE balance <> 0
+        UNION ALL
+        SELECT journal_id, user_id, asset, $2, locked, 0, $4, $5 FROM opening WHERE locked <> 0
+        UNION ALL
+        SELECT journal_id, user_id, asset, $3, 0, balance + locked, $4, $5 FROM opening`
+
+	result := db.Exec(query, domain.LedgerAvailable, domain.LedgerLocked, domain.LedgerDeposits,
+		domain.LedgerKindOpening, time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to open the ledger balances: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -10,28 +10,47 @@ import (
 	"github.com/kannan112/mock-trading-platform-api/pkg/config"
 	"github.com/kannan112/mock-trading-platform-api/pkg/db"
 	"github.com/kannan112/mock-trading-platform-api/pkg/repository"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/notify"
 	"github.com/kannan112/mock-trading-platform-api/pkg/service/token"
 	"github.com/kannan112/mock-trading-platform-api/pkg/usecase"
+	"github.com/kannan112/mock-trading-platform-api/pkg/worker"
 )
 
-func InitializeApi(cfg config.Config) (*http.ServerHTTP, error) {
+func InitializeApi(cfg config.Config) (*http.ServerHTTP, func(), error) {
 
 	wire.Build(db.ConnectDatabase,
 		//external
 		token.NewTokenService,
+		marketdata.NewMarketDataProvider,
+		marketdata.NewHub,
+		notify.NewNotifier,
 
 		// repository
 		repository.NewOrderRepository,
 		repository.NewUserRepository,
+		repository.NewAccountRepository,
+		repository.NewTradeRepository,
+		repository.NewPortfolioRepository,
+		repository.NewSymbolRepository,
+		repository.NewRiskRepository,
 
 		//usecase
 		usecase.NewUserUseCase,
+		usecase.NewOrderUseCase,
+		usecase.NewWalletUseCase,
+		usecase.NewPortfolioUseCase,
+		usecase.NewSymbolUseCase,
+		usecase.NewRiskUseCase,
 
 		// handler
 		handler.NewUserHandler,
 
+		// background jobs
+		worker.NewWorker,
+
 		http.NewServerHTTP,
 	)
 
-	return &http.ServerHTTP{}, nil
+	return &http.ServerHTTP{}, nil, nil
 }
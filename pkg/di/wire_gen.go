@@ -12,22 +12,48 @@ import (
 	"github.com/kannan112/mock-trading-platform-api/pkg/config"
 	"github.com/kannan112/mock-trading-platform-api/pkg/db"
 	"github.com/kannan112/mock-trading-platform-api/pkg/repository"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/notify"
 	"github.com/kannan112/mock-trading-platform-api/pkg/service/token"
 	"github.com/kannan112/mock-trading-platform-api/pkg/usecase"
+	"github.com/kannan112/mock-trading-platform-api/pkg/worker"
 )
 
 // Injectors from wire.go:
 
-func InitializeApi(cfg config.Config) (*http.ServerHTTP, error) {
+func InitializeApi(cfg config.Config) (*http.ServerHTTP, func(), error) {
 	gormDB, err := db.ConnectDatabase(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	userRepository := repository.NewUserRepository(gormDB)
 	tokenService := token.NewTokenService(cfg)
+	marketDataProvider, cleanup, err := marketdata.NewMarketDataProvider(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	accountRepository := repository.NewAccountRepository(gormDB)
+	symbolRepository := repository.NewSymbolRepository(gormDB)
+	userUseCase := usecase.NewUserUseCase(cfg, userRepository, accountRepository, symbolRepository, tokenService)
 	orderRepository := repository.NewOrderRepository(gormDB)
-	userUseCase := usecase.NewUserUseCase(userRepository, tokenService, orderRepository)
-	userHandler := handler.NewUserHandler(userUseCase, tokenService)
-	serverHTTP := http.NewServerHTTP(userHandler)
-	return serverHTTP, nil
+	tradeRepository := repository.NewTradeRepository(gormDB)
+	riskRepository := repository.NewRiskRepository(gormDB)
+	notifier := notify.NewNotifier(cfg)
+	portfolioRepository := repository.NewPortfolioRepository(gormDB)
+	orderUseCase, err := usecase.NewOrderUseCase(cfg, orderRepository, accountRepository, tradeRepository, userRepository, symbolRepository, riskRepository, portfolioRepository, marketDataProvider, notifier)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	walletUseCase := usecase.NewWalletUseCase(accountRepository, userRepository, marketDataProvider)
+	portfolioUseCase := usecase.NewPortfolioUseCase(portfolioRepository, tradeRepository, accountRepository, marketDataProvider)
+	symbolUseCase := usecase.NewSymbolUseCase(cfg, symbolRepository, marketDataProvider)
+	riskUseCase := usecase.NewRiskUseCase(cfg, riskRepository, notifier)
+	hub := marketdata.NewHub(marketDataProvider, cfg)
+	userHandler := handler.NewUserHandler(userUseCase, orderUseCase, walletUseCase, portfolioUseCase, symbolUseCase, riskUseCase, tokenService, hub, notifier)
+	workerWorker := worker.NewWorker(cfg, orderUseCase, walletUseCase, portfolioUseCase, symbolUseCase)
+	serverHTTP := http.NewServerHTTP(cfg, userHandler, workerWorker)
+	return serverHTTP, func() {
+		cleanup()
+	}, nil
 }
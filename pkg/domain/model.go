@@ -3,58 +3,368 @@ package domain
 import "time"
 
 type User struct {
-	ID        uint       `gorm:"primaryKey"`
-	Username  string     `gorm:"unique;not null"`
-	Email     string     `gorm:"unique;not null"`
-	Password  string     `gorm:"not null"`
-	CreatedAt time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
-	Accounts  []Account  `gorm:"foreignKey:UserID"`
-	Orders    []Order    `gorm:"foreignKey:UserID"`
-	Positions []Position `gorm:"foreignKey:UserID"`
-	Trades    []Trade    `gorm:"foreignKey:UserID"`
+	ID       uint   `gorm:"primaryKey"`
+	Username string `gorm:"unique;not null"`
+	Email    string `gorm:"unique;not null"`
+	Password string `gorm:"not null"`
+	// how the trades of the user close the lots of their positions
+	CostBasisMethod string     `gorm:"not null;default:FIFO"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime"`
+	Accounts        []Account  `gorm:"foreignKey:UserID"`
+	Orders          []Order    `gorm:"foreignKey:UserID"`
+	Positions       []Position `gorm:"foreignKey:UserID"`
+	Trades          []Trade    `gorm:"foreignKey:UserID"`
 }
 
-// this is for adding multiple accounts for the user
+// cost basis methods, a closing trade close the oldest lots first, the newest lots first,
+// or every lot in proportion at their weighted average price
+const (
+	CostBasisFIFO    = "FIFO"
+	CostBasisLIFO    = "LIFO"
+	CostBasisAverage = "AVERAGE"
+)
+
+// this is for adding multiple accounts for the user, one for each asset. Balance is free
+// to use and Locked is reserved by the live orders of the user, both are kept in step with
+// the ledger entries of the account and never changed without them
 type Account struct {
 	ID        uint      `gorm:"primaryKey"`
-	UserID    uint      `gorm:"not null;index"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_accounts_user_asset"`
+	Asset     string    `gorm:"not null;default:USDT;uniqueIndex:idx_accounts_user_asset"`
 	Balance   float64   `gorm:"not null"`
+	Locked    float64   `gorm:"not null;default:0"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
 
-// Pending ===== order
-type Order struct {
-	ID        uint      `gorm:"primaryKey"`
-	OrderUUID string    `gorm:"not null;index"`
-	UserID    uint      `gorm:"not null;index"`
-	Symbol    string    `gorm:"not null"`
-	Volume    float64   `gorm:"not null"`
-	Type      string    `gorm:"not null"`
-	Price     float64   `gorm:"not null"`
-	Status    string    `gorm:"not null"`
+// ledger accounts, the available and locked balances of the users and the accounts their
+// funds come from and go to. a deposit come from deposits and a fill trade with the exchange
+const (
+	LedgerAvailable = "available"
+	LedgerLocked    = "locked"
+	LedgerDeposits  = "deposits"
+	LedgerExchange  = "exchange"
+	LedgerFees      = "fees"
+)
+
+// kinds of balance movements
+const (
+	LedgerKindDeposit    = "deposit"
+	LedgerKindReserve    = "reserve"
+	LedgerKindRelease    = "release"
+	LedgerKindFill       = "fill"
+	LedgerKindFee        = "fee"
+	LedgerKindAdjustment = "adjustment"
+	// the balance an account had before the ledger, posted once from deposits
+	LedgerKindOpening = "opening"
+)
+
+// LedgerEntry is one side of a balance movement, the entries of a journal debit and credit the
+// same amount of each asset. Debit increase the available and locked balances of a user and Credit
+// decrease them, so a balance is the sum of its debits less its credits.
+type LedgerEntry struct {
+	ID        uint    `gorm:"primaryKey"`
+	JournalID string  `gorm:"not null;index"`
+	UserID    uint    `gorm:"not null;index:idx_ledger_entries_user_asset"`
+	Asset     string  `gorm:"not null;index:idx_ledger_entries_user_asset"`
+	Account   string  `gorm:"not null"`
+	Debit     float64 `gorm:"not null;default:0"`
+	Credit    float64 `gorm:"not null;default:0"`
+	Kind      string  `gorm:"not null"`
+	// order or trade the movement came from
+	OrderID   *uint     `gorm:"index"`
+	TradeID   *uint     `gorm:"index"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
 
+// order kinds, stop and take profit orders wait for their trigger price and
+// then place a market order, or a limit order for stop limit
+const (
+	OrderKindMarket     = "market"
+	OrderKindLimit      = "limit"
+	OrderKindStopMarket = "stop_market"
+	OrderKindStopLimit  = "stop_limit"
+	OrderKindTakeProfit = "take_profit"
+	// stop market order whose trigger follow the best price by an offset
+	OrderKindTrailingStop = "trailing_stop"
+)
+
+// order sides, stored on the order type
+const (
+	OrderSideBuy  = "buy"
+	OrderSideSell = "sell"
+)
+
+// order statuses, a limit order rest as open until the market price cross its limit
+// and a stop or take profit order is pending until the market price reach its trigger
+const (
+	OrderStatusPending         = "pending"
+	OrderStatusTriggered       = "triggered"
+	OrderStatusOpen            = "open"
+	OrderStatusPartiallyFilled = "partially_filled"
+	OrderStatusFilled          = "filled"
+	OrderStatusCancelled       = "cancelled"
+	OrderStatusExpired         = "expired"
+)
+
+// time in force of an order, good till cancelled (default), immediate or cancel, fill or kill
+// and good till date. IOC and FOK apply to limit orders, and to the order placed by a stop limit
+const (
+	TimeInForceGTC = "GTC"
+	TimeInForceIOC = "IOC"
+	TimeInForceFOK = "FOK"
+	TimeInForceGTD = "GTD"
+)
+
+// reasons an order ended with
+const (
+	OrderEndFilled         = "filled"
+	OrderEndTriggered      = "triggered"
+	OrderEndUserCancelled  = "user_cancelled"
+	OrderEndGroupCancelled = "group_cancelled"
+	OrderEndSiblingFilled  = "sibling_filled"
+	OrderEndIOCUnfilled    = "ioc_unfilled"
+	OrderEndFOKUnfilled    = "fok_unfilled"
+	OrderEndExpired        = "expired"
+	// the order book ran out before the market order was filled
+	OrderEndLiquidityExhausted = "liquidity_exhausted"
+	// the daily loss of the user tripped their kill switch
+	OrderEndStopOut = "stop_out"
+)
+
+// Price is the average fill price of the order
+type Order struct {
+	ID           uint    `gorm:"primaryKey"`
+	OrderUUID    string  `gorm:"not null;index"`
+	UserID       uint    `gorm:"not null;index"`
+	Symbol       string  `gorm:"not null"`
+	Volume       float64 `gorm:"not null"`
+	Type         string  `gorm:"not null"`
+	Kind         string  `gorm:"not null;default:market"`
+	Price        float64 `gorm:"not null"`
+	LimitPrice   float64 `gorm:"not null;default:0"`
+	TriggerPrice float64 `gorm:"not null;default:0"`
+	// distance of a trailing stop trigger from the best price seen since placement,
+	// absolute or in percent of the best price
+	TrailingOffset  float64 `gorm:"not null;default:0"`
+	TrailingPercent float64 `gorm:"not null;default:0"`
+	BestPrice       float64 `gorm:"not null;default:0"`
+	FilledVolume    float64 `gorm:"not null;default:0"`
+	// funds still reserved by the order, quote asset for a buy and base asset for a sell
+	Reserved float64 `gorm:"not null;default:0"`
+	// fees paid by the fills of the order, in the quote asset
+	Fee         float64 `gorm:"not null;default:0"`
+	Status      string  `gorm:"not null;index"`
+	TimeInForce string  `gorm:"not null;default:GTC"`
+	ExpiresAt   *time.Time
+	EndReason   string `gorm:"not null;default:''"`
+	// order which placed this one when triggered
+	ParentOrderID *uint `gorm:"index"`
+	// order group the order is a leg of
+	GroupID     *uint  `gorm:"index"`
+	GroupLeg    string `gorm:"not null;default:''"`
+	TriggeredAt *time.Time
+	FilledAt    *time.Time
+	CancelledAt *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// order group types, a bracket enter with one order and exit with an OCO pair placed once
+// the entry is filled, or for its filled part when it end before. an OCO is the exit pair alone
+const (
+	OrderGroupBracket = "bracket"
+	OrderGroupOCO     = "oco"
+)
+
+// legs of an order group, the target is a limit order and the stop a stop market or stop limit order
+const (
+	OrderLegEntry  = "entry"
+	OrderLegTarget = "target"
+	OrderLegStop   = "stop"
+)
+
+// order group statuses, a group is completed once one of its exit legs fill or trigger
+const (
+	OrderGroupStatusActive    = "active"
+	OrderGroupStatusCompleted = "completed"
+	OrderGroupStatusCancelled = "cancelled"
+)
+
+// OrderGroup link the orders of a bracket or OCO, Side is the side of the exit legs
+type OrderGroup struct {
+	ID              uint    `gorm:"primaryKey"`
+	GroupUUID       string  `gorm:"not null;index"`
+	UserID          uint    `gorm:"not null;index"`
+	Type            string  `gorm:"not null"`
+	Symbol          string  `gorm:"not null"`
+	Side            string  `gorm:"not null"`
+	Volume          float64 `gorm:"not null"`
+	TakeProfitPrice float64 `gorm:"not null"`
+	StopPrice       float64 `gorm:"not null"`
+	StopLimitPrice  float64 `gorm:"not null;default:0"`
+	Status          string  `gorm:"not null;index"`
+	// exit leg which completed the group
+	FilledOrderID *uint
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+// OrderAmendment record a change of the quantity or prices of a resting order
+type OrderAmendment struct {
+	ID              uint      `gorm:"primaryKey"`
+	OrderID         uint      `gorm:"not null;index"`
+	UserID          uint      `gorm:"not null;index"`
+	OldVolume       float64   `gorm:"not null"`
+	NewVolume       float64   `gorm:"not null"`
+	OldLimitPrice   float64   `gorm:"not null"`
+	NewLimitPrice   float64   `gorm:"not null"`
+	OldTriggerPrice float64   `gorm:"not null"`
+	NewTriggerPrice float64   `gorm:"not null"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+// Position is the holding of a symbol built by the trades of the user, EntryPrice is the average
+// price the volume was bought at
 type Position struct {
 	ID            uint      `gorm:"primaryKey"`
-	UserID        uint      `gorm:"not null;index"`
-	Symbol        string    `gorm:"not null"`
+	UserID        uint      `gorm:"not null;uniqueIndex:idx_positions_user_symbol"`
+	Symbol        string    `gorm:"not null;uniqueIndex:idx_positions_user_symbol"`
 	Volume        float64   `gorm:"not null"`
 	EntryPrice    float64   `gorm:"not null"`
 	UnrealizedPnl float64   `gorm:"not null"`
+	RealizedPnl   float64   `gorm:"not null;default:0"`
 	CreatedAt     time.Time `gorm:"autoCreateTime"`
 	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
 }
 
+// Trade is one fill of an order
 type Trade struct {
-	ID        uint      `gorm:"primaryKey"`
-	UserID    uint      `gorm:"not null;index"`
-	Symbol    string    `gorm:"not null"`
+	ID      uint    `gorm:"primaryKey"`
+	UserID  uint    `gorm:"not null;index"`
+	OrderID uint    `gorm:"not null;index"`
+	Symbol  string  `gorm:"not null"`
+	Volume  float64 `gorm:"not null"`
+	Price   float64 `gorm:"not null"`
+	Type    string  `gorm:"not null"`
+	// profit of the lots the trade closed
+	RealizedPnl float64 `gorm:"not null;default:0"`
+	// maker when the order rested in the book before the fill, taker when it crossed it.
+	// the fee is charged in the quote asset at FeeRate of the trade value
+	Liquidity string    `gorm:"not null;default:taker"`
+	FeeRate   float64   `gorm:"not null;default:0"`
+	Fee       float64   `gorm:"not null;default:0"`
+	FeeAsset  string    `gorm:"not null;default:''"`
+	Timestamp time.Time `gorm:"not null"`
+}
+
+// liquidity of a fill
+const (
+	LiquidityMaker = "maker"
+	LiquidityTaker = "taker"
+)
+
+// TaxLot is the volume a trade opened a position with, Remaining is what the closing trades left of it.
+// Side is the side of the opening trade
+type TaxLot struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"not null;index:idx_tax_lots_user_symbol"`
+	Symbol string `gorm:"not null;index:idx_tax_lots_user_symbol"`
+	// nil for a lot carried over from a position opened before lots were tracked
+	TradeID   *uint     `gorm:"index"`
+	Side      string    `gorm:"not null"`
 	Volume    float64   `gorm:"not null"`
+	Remaining float64   `gorm:"not null"`
 	Price     float64   `gorm:"not null"`
-	Type      string    `gorm:"not null"`
-	Timestamp time.Time `gorm:"not null"`
+	OpenedAt  time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// LotMatch is the volume of a lot a trade closed and the profit it realized
+type LotMatch struct {
+	ID          uint      `gorm:"primaryKey"`
+	UserID      uint      `gorm:"not null;index"`
+	TradeID     uint      `gorm:"not null;index"`
+	LotID       uint      `gorm:"not null;index"`
+	Method      string    `gorm:"not null"`
+	Volume      float64   `gorm:"not null"`
+	OpenPrice   float64   `gorm:"not null"`
+	ClosePrice  float64   `gorm:"not null"`
+	RealizedPnl float64   `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+// EquitySnapshot is the value of the accounts of a user at a time, Cash is the quote asset
+// and PositionsValue the other assets at their bid
+type EquitySnapshot struct {
+	ID             uint      `gorm:"primaryKey"`
+	UserID         uint      `gorm:"not null;index:idx_equity_snapshots_user_taken"`
+	Cash           float64   `gorm:"not null"`
+	PositionsValue float64   `gorm:"not null"`
+	Equity         float64   `gorm:"not null"`
+	TakenAt        time.Time `gorm:"not null;index:idx_equity_snapshots_user_taken"`
+}
+
+// Symbol is a market of the symbol registry with the exchange filters its orders are rounded and
+// checked against, a filter left at zero is not checked
+type Symbol struct {
+	Symbol     string `gorm:"primaryKey"`
+	Status     string `gorm:"not null"`
+	BaseAsset  string `gorm:"not null"`
+	QuoteAsset string `gorm:"not null"`
+	// prices are multiples of the tick size and quantities of the step size
+	TickSize    float64   `gorm:"not null;default:0"`
+	StepSize    float64   `gorm:"not null;default:0"`
+	MinQty      float64   `gorm:"not null;default:0"`
+	MaxQty      float64   `gorm:"not null;default:0"`
+	MinNotional float64   `gorm:"not null;default:0"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// RiskLimits is the pre-trade risk limits a user set for their orders, a limit left at zero is not enforced.
+// users without their own limits get the defaults of the config
+type RiskLimits struct {
+	UserID uint `gorm:"primaryKey"`
+	// value of one order and of the position in a symbol, in the quote asset
+	MaxOrderNotional float64 `gorm:"not null;default:0"`
+	MaxPositionSize  float64 `gorm:"not null;default:0"`
+	MaxOpenOrders    int     `gorm:"not null;default:0"`
+	// value traded since the start of the UTC day, in the quote asset
+	MaxDailyVolume float64 `gorm:"not null;default:0"`
+	// fraction of the mark price a limit price may be away from it
+	PriceBand float64 `gorm:"not null;default:0"`
+	// realized and unrealized loss of a UTC day that stop the user out, and whether their positions are
+	// closed when it does
+	MaxDailyLoss     float64   `gorm:"not null;default:0"`
+	FlattenOnStopOut bool      `gorm:"not null;default:false"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime"`
+}
+
+// pre-trade risk rules, an order breaking one of them is refused
+const (
+	RiskRuleMaxOrderNotional = "max_order_notional"
+	RiskRuleMaxPositionSize  = "max_position_size"
+	RiskRuleMaxOpenOrders    = "max_open_orders"
+	RiskRuleMaxDailyVolume   = "max_daily_volume"
+	RiskRulePriceBand        = "price_band"
+	RiskRuleDailyLoss        = "daily_loss_limit"
+)
+
+// StopOut is the kill switch of a user tripped by their daily loss, it cancelled their live orders and
+// refuse new ones for the rest of the trading day, a UTC day, unless an admin reset it
+type StopOut struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;index:idx_stop_outs_user_day"`
+	TradingDay time.Time `gorm:"not null;index:idx_stop_outs_user_day"`
+	// loss of the day when it tripped, of the trades closed and of the open positions at the mark
+	Loss          float64 `gorm:"not null"`
+	LossLimit     float64 `gorm:"not null"`
+	RealizedPnl   float64 `gorm:"not null"`
+	UnrealizedPnl float64 `gorm:"not null"`
+	// the positions were closed
+	Flatten   bool       `gorm:"not null;default:false"`
+	ResetAt   *time.Time `gorm:"index"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
 }
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// debits and credits of a journal may differ by this much of an asset
+const ledgerTolerance = 1e-9
+
+// a posting took an available balance below zero
+var errInsufficientBalance = errors.New("insufficient balance")
+
+type accountDatabase struct {
+	DB *gorm.DB
+}
+
+func NewAccountRepository(DB *gorm.DB) interfaces.AccountRepository {
+	return &accountDatabase{DB: DB}
+}
+
+// account of the asset, an empty one if the user never held it
+func (c *accountDatabase) GetAccount(ctx context.Context, uid uint, asset string) (domain.Account, error) {
+	account := domain.Account{UserID: uid, Asset: asset}
+
+	query := `SELECT * FROM accounts WHERE user_id = $1 AND asset = $2 LIMIT 1`
+
+	err := conn(c.DB, ctx).Raw(query, uid, asset).Scan(&account).Error
+	if err != nil {
+		return domain.Account{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
+	return account, nil
+}
+
+// accounts of every asset the user held, by asset
+func (c *accountDatabase) GetAccounts(ctx context.Context, uid uint) ([]domain.Account, error) {
+	var accounts []domain.Account
+
+	query := `SELECT * FROM accounts WHERE user_id = $1 ORDER BY asset`
+
+	err := conn(c.DB, ctx).Raw(query, uid).Scan(&accounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+func (c *accountDatabase) GetAllAccounts(ctx context.Context) ([]domain.Account, error) {
+	var accounts []domain.Account
+
+	query := `SELECT * FROM accounts ORDER BY user_id, asset`
+
+	err := conn(c.DB, ctx).Raw(query).Scan(&accounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// account balances moved by a journal
+type balanceMove struct {
+	userID    uint
+	asset     string
+	available float64
+	locked    float64
+}
+
+// Post save the entries as one journal and move the available and locked balances of the users
+// by them, all or nothing. false when an available balance would go below zero.
+func (c *accountDatabase) Post(ctx context.Context, entries []domain.LedgerEntry) (bool, error) {
+
+	moves, err := balanceMoves(entries)
+	if err != nil {
+		return false, err
+	}
+
+	journalID := uuid.New().String()
+	now := time.Now()
+
+	// a savepoint inside the transaction of the ctx, so a refused posting leave nothing behind
+	err = conn(c.DB, ctx).Transaction(func(tx *gorm.DB) error {
+		for _, move := range moves {
+			if err := moveBalance(tx, move, now); err != nil {
+				return err
+			}
+		}
+
+		query := `
+        INSERT INTO ledger_entries (journal_id, user_id, asset, account, debit, credit, kind, order_id, trade_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+		for _, entry := range entries {
+			err := tx.Exec(query, journalID, entry.UserID, entry.Asset, entry.Account, entry.Debit, entry.Credit,
+				entry.Kind, entry.OrderID, entry.TradeID, now).Error
+			if err != nil {
+				return fmt.Errorf("failed to save ledger entry: %w", err)
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errInsufficientBalance) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// check the entries balance for each asset and sum what they move on each user account
+func balanceMoves(entries []domain.LedgerEntry) ([]balanceMove, error) {
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("empty ledger journal")
+	}
+
+	net := make(map[string]float64)
+	var moves []balanceMove
+
+	for _, entry := range entries {
+		if entry.Debit < 0 || entry.Credit < 0 || (entry.Debit > 0) == (entry.Credit > 0) {
+			return nil, fmt.Errorf("ledger entry of %s %s must either debit or credit a positive amount", entry.Asset, entry.Account)
+		}
+		net[entry.Asset] += entry.Debit - entry.Credit
+
+		if entry.Account != domain.LedgerAvailable && entry.Account != domain.LedgerLocked {
+			continue
+		}
+
+		i := 0
+		for i < len(moves) && (moves[i].userID != entry.UserID || moves[i].asset != entry.Asset) {
+			i++
+		}
+		if i == len(moves) {
+			moves = append(moves, balanceMove{userID: entry.UserID, asset: entry.Asset})
+		}
+		if entry.Account == domain.LedgerAvailable {
+			moves[i].available += entry.Debit - entry.Credit
+		} else {
+			moves[i].locked += entry.Debit - entry.Credit
+		}
+	}
+
+	for asset, amount := range net {
+		if math.Abs(amount) > ledgerTolerance {
+			return nil, fmt.Errorf("ledger journal of %s is off balance by %v", asset, amount)
+		}
+	}
+	return moves, nil
+}
+
+// apply a move to the account, creating the account when the move only add to it
+func moveBalance(tx *gorm.DB, move balanceMove, now time.Time) error {
+
+	if move.available >= 0 && move.locked >= 0 {
+		query := `
+        INSERT INTO accounts (user_id, asset, balance, locked, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $5)
+        ON CONFLICT (user_id, asset) DO UPDATE
+        SET balance = accounts.balance + EXCLUDED.balance, locked = accounts.locked + EXCLUDED.locked, updated_at = $5`
+
+		err := tx.Exec(query, move.userID, move.asset, move.available, move.locked, now).Error
+		if err != nil {
+			return fmt.Errorf("failed to update account: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+        UPDATE accounts SET balance = balance + $1, locked = locked + $2, updated_at = $3
+        WHERE user_id = $4 AND asset = $5 AND balance + $1 >= 0`
+
+	result := tx.Exec(query, move.available, move.locked, now, move.userID, move.asset)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update account: %w", result.Error)
+	}
+	if result.RowsAffected != 1 {
+		return errInsufficientBalance
+	}
+	return nil
+}
+
+// ledger entries of the user, newest first, of one asset when given
+func (c *accountDatabase) GetLedgerEntries(ctx context.Context, uid uint, asset string) ([]utils.LedgerEntry, error) {
+	var entries []utils.LedgerEntry
+
+	query := `
+        SELECT id, journal_id, asset, account, debit, credit, kind, order_id, trade_id, created_at
+        FROM ledger_entries
+        WHERE user_id = $1 AND account IN ($2, $3) AND ($4 = '' OR asset = $4)
+        ORDER BY id DESC`
+
+	err := conn(c.DB, ctx).Raw(query, uid, domain.LedgerAvailable, domain.LedgerLocked, asset).Scan(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+// accounts whose balances differ from the sum of their ledger entries
+func (c *accountDatabase) GetAccountDrifts(ctx context.Context) ([]utils.AccountDrift, error) {
+	var drifts []utils.AccountDrift
+
+	query := `
+        SELECT a.user_id, a.asset, a.balance, a.locked,
+            COALESCE(l.ledger_balance, 0) AS ledger_balance, COALESCE(l.ledger_locked, 0) AS ledger_locked
+        FROM accounts a
+        LEFT JOIN (
+            SELECT user_id, asset,
+                SUM(CASE WHEN account = $1 THEN debit - credit ELSE 0 END) AS ledger_balance,
+                SUM(CASE WHEN account = $2 THEN debit - credit ELSE 0 END) AS ledger_locked
+            FROM ledger_entries
+            WHERE account IN ($1, $2)
+            GROUP BY user_id, asset
+        ) l ON l.user_id = a.user_id AND l.asset = a.asset
+        WHERE ABS(a.balance - COALESCE(l.ledger_balance, 0)) > $3
+            OR ABS(a.locked - COALESCE(l.ledger_locked, 0)) > $3
+        ORDER BY a.user_id, a.asset`
+
+	err := conn(c.DB, ctx).Raw(query, domain.LedgerAvailable, domain.LedgerLocked, ledgerTolerance).Scan(&drifts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile accounts: %w", err)
+	}
+	return drifts, nil
+}
+
+// journals whose debits and credits of an asset don't add up
+func (c *accountDatabase) GetUnbalancedJournals(ctx context.Context) ([]utils.UnbalancedJournal, error) {
+	var journals []utils.UnbalancedJournal
+
+	query := `
+        SELECT journal_id, asset, SUM(debit) AS debit, SUM(credit) AS credit
+        FROM ledger_entries
+        GROUP BY journal_id, asset
+        HAVING ABS(SUM(debit) - SUM(credit)) > $1
+        ORDER BY journal_id, asset`
+
+	err := conn(c.DB, ctx).Raw(query, ledgerTolerance).Scan(&journals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ledger journals: %w", err)
+	}
+	return journals, nil
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+type AccountRepository interface {
+	GetAccount(ctx context.Context, uid uint, asset string) (domain.Account, error)
+	GetAccounts(ctx context.Context, uid uint) ([]domain.Account, error)
+	// accounts of every user, by user
+	GetAllAccounts(ctx context.Context) ([]domain.Account, error)
+
+	// save the balanced entries as one journal and move the account balances by them,
+	// false when an available balance would go below zero
+	Post(ctx context.Context, entries []domain.LedgerEntry) (bool, error)
+	GetLedgerEntries(ctx context.Context, uid uint, asset string) ([]utils.LedgerEntry, error)
+
+	// reconciliation of the account balances with the ledger
+	GetAccountDrifts(ctx context.Context) ([]utils.AccountDrift, error)
+	GetUnbalancedJournals(ctx context.Context) ([]utils.UnbalancedJournal, error)
+}
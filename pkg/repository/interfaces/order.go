@@ -2,14 +2,40 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
-	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
 	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
 )
 
 type OrderRepository interface {
-	PlaceOrder(ctx context.Context, uid int, data response.OrderResponse) (int, error)
+	// run fn in a database transaction shared by the repositories called with its ctx
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	PlaceOrder(ctx context.Context, order domain.Order) (uint, error)
 	GetAllOrders(uid int) ([]utils.OrderResponse, error)
 	GetOrderByID(oid, uid uint) (utils.Order, error)
-	DeleteOrderById(oid, uid uint) error
+	GetChildOrders(ctx context.Context, oid uint) ([]utils.Order, error)
+	GetOrder(ctx context.Context, oid, uid uint) (domain.Order, error)
+
+	GetActiveOrders(ctx context.Context) ([]domain.Order, error)
+	// orders of the user still waiting to trigger or fill
+	GetLiveOrders(ctx context.Context, uid uint) ([]domain.Order, error)
+	CountLiveOrders(ctx context.Context, uid uint) (int, error)
+	UpdateOrderFill(ctx context.Context, order domain.Order, prevFilledVolume float64) (bool, error)
+	TriggerOrder(ctx context.Context, oid uint, triggeredAt time.Time) (bool, error)
+	UpdateTrailingStop(ctx context.Context, oid uint, bestPrice, triggerPrice float64) error
+	CancelOrder(ctx context.Context, oid uint, reason string) (bool, error)
+	GetExpiredOrders(ctx context.Context, now time.Time) ([]domain.Order, error)
+	ExpireOrder(ctx context.Context, oid uint) (bool, error)
+	AmendOrder(ctx context.Context, order domain.Order, amendment domain.OrderAmendment) (bool, error)
+	GetOrderAmendments(ctx context.Context, oid uint) ([]utils.OrderAmendment, error)
+
+	CreateOrderGroup(ctx context.Context, group domain.OrderGroup) (uint, error)
+	GetOrderGroup(ctx context.Context, gid, uid uint) (domain.OrderGroup, error)
+	GetOrderGroups(ctx context.Context, uid uint) ([]domain.OrderGroup, error)
+	GetGroupOrders(ctx context.Context, gid uint) ([]utils.OrderResponse, error)
+	GetLiveGroupOrders(ctx context.Context, gid uint) ([]domain.Order, error)
+	ClaimOrderGroup(ctx context.Context, gid, oid uint) (bool, error)
+	CancelOrderGroup(ctx context.Context, gid, uid uint) (bool, error)
 }
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+)
+
+type PortfolioRepository interface {
+	CreateEquitySnapshots(ctx context.Context, snapshots []domain.EquitySnapshot) error
+	// snapshots of the user taken from to to, oldest first
+	GetEquitySnapshots(ctx context.Context, uid uint, from, to time.Time) ([]domain.EquitySnapshot, error)
+	// first snapshot of the user taken since since, a zero one when there is none
+	GetFirstEquitySnapshot(ctx context.Context, uid uint, since time.Time) (domain.EquitySnapshot, error)
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+type RiskRepository interface {
+	// risk limits the user set, zero limits when they set none
+	GetRiskLimits(ctx context.Context, uid uint) (domain.RiskLimits, error)
+	SaveRiskLimits(ctx context.Context, limits domain.RiskLimits) error
+	// hold the risk lock of the user until the transaction of ctx end
+	LockUser(ctx context.Context, uid uint) error
+
+	CreateStopOut(ctx context.Context, stopOut domain.StopOut) (uint, error)
+	// stop out of the user on the trading day, reset or not, a zero one when they weren't stopped out
+	GetStopOut(ctx context.Context, uid uint, tradingDay time.Time) (domain.StopOut, error)
+	// reset the stop out of the user on the trading day, false when there is none to reset
+	ResetStopOut(ctx context.Context, uid uint, tradingDay time.Time) (bool, error)
+	GetStopOuts(ctx context.Context, uid uint) ([]utils.StopOut, error)
+}
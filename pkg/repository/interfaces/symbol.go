@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+type SymbolRepository interface {
+	// add the symbols to the registry or update them
+	SaveSymbols(ctx context.Context, symbols []domain.Symbol) error
+	// symbol of the registry, a zero symbol when it's not registered
+	GetSymbol(ctx context.Context, symbol string) (domain.Symbol, error)
+	GetSymbols(ctx context.Context) ([]utils.Symbol, error)
+}
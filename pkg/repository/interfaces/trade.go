@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+type TradeRepository interface {
+	CreateTrade(ctx context.Context, trade domain.Trade) (uint, error)
+	SetTradeRealizedPnl(ctx context.Context, tid uint, pnl float64) error
+	GetUserTrades(ctx context.Context, uid uint) ([]utils.Trade, error)
+	GetTradedValue(ctx context.Context, uid uint, since time.Time) (float64, error)
+	GetRealizedPnl(ctx context.Context, uid uint, since time.Time) (float64, error)
+	// users with an open position or a trade since since
+	GetTradingUsers(ctx context.Context, since time.Time) ([]uint, error)
+	// trades of the user which closed lots from to to, oldest first
+	GetClosingTrades(ctx context.Context, uid uint, from, to time.Time) ([]utils.Trade, error)
+
+	// position of the symbol locked until the end of the transaction, an empty one if the user never traded it
+	GetPositionForUpdate(ctx context.Context, uid uint, symbol string) (domain.Position, error)
+	SavePosition(ctx context.Context, position domain.Position) error
+	GetOpenPositions(ctx context.Context, uid uint) ([]domain.Position, error)
+
+	// open lots of the position, oldest first and locked until the end of the transaction
+	GetOpenLots(ctx context.Context, uid uint, symbol string) ([]domain.TaxLot, error)
+	CreateLot(ctx context.Context, lot domain.TaxLot) (uint, error)
+	UpdateLotRemaining(ctx context.Context, lid uint, remaining float64) error
+	CreateLotMatch(ctx context.Context, match domain.LotMatch) error
+	GetUserLotMatches(ctx context.Context, uid uint) ([]utils.LotMatch, error)
+}
@@ -9,10 +9,16 @@ import (
 
 //go:generate mockgen -destination=../../mock/mockrepo/user_mock.go -package=mockrepo . UserRepository
 type UserRepository interface {
+	// run fn in a database transaction shared by the repositories called with its ctx
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+
 	FindUserByUserID(ctx context.Context, userID uint) (user domain.User, err error)
 	FindUserByEmail(ctx context.Context, email string) (bool, error)
 	ExtractPassword(ctx context.Context, email string) (string, error)
 
 	GetUserId(ctx context.Context, email string) (int, error)
 	SaveUser(ctx context.Context, user request.RegisterUserRequest) (userID uint, err error)
+
+	GetCostBasisMethod(ctx context.Context, userID uint) (string, error)
+	SetCostBasisMethod(ctx context.Context, userID uint, method string) error
 }
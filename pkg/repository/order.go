@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
 	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
 	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
 	"gorm.io/gorm"
@@ -19,15 +19,24 @@ func NewOrderRepository(DB *gorm.DB) interfaces.OrderRepository {
 	return &orderDatabase{DB: DB}
 }
 
+func (c *orderDatabase) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return transaction(c.DB, ctx, fn)
+}
+
 // need to add gorm model to
-func (c *orderDatabase) PlaceOrder(ctx context.Context, uid int, data response.OrderResponse) (int, error) {
-	fmt.Println(data, "data")
-	var oid int
-	query := `INSERT INTO orders (order_uuid, user_id, symbol, volume, type, price, status, created_at) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)RETURNING id`
+func (c *orderDatabase) PlaceOrder(ctx context.Context, order domain.Order) (uint, error) {
+	var oid uint
+	query := `INSERT INTO orders (order_uuid, user_id, symbol, volume, type, kind, price, limit_price, trigger_price, 
+	trailing_offset, trailing_percent, best_price, filled_volume, reserved, status, time_in_force, expires_at, end_reason, 
+	parent_order_id, group_id, group_leg, triggered_at, filled_at, created_at, updated_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $24) 
+	RETURNING id`
 
 	createdAt := time.Now()
-	err := c.DB.Raw(query, data.OrderUUID, uid, data.Symbol, data.Volume, data.Type, data.Price, data.Status, createdAt).Scan(&oid).Error
+	err := conn(c.DB, ctx).Raw(query, order.OrderUUID, order.UserID, order.Symbol, order.Volume, order.Type, order.Kind, order.Price,
+		order.LimitPrice, order.TriggerPrice, order.TrailingOffset, order.TrailingPercent, order.BestPrice, order.FilledVolume,
+		order.Reserved, order.Status, order.TimeInForce, order.ExpiresAt, order.EndReason, order.ParentOrderID, order.GroupID,
+		order.GroupLeg, order.TriggeredAt, order.FilledAt, createdAt).Scan(&oid).Error
 	return oid, err
 }
 
@@ -35,7 +44,9 @@ func (c *orderDatabase) GetAllOrders(uid int) ([]utils.OrderResponse, error) {
 	var dbOrders []utils.Order
 
 	query := `
-        SELECT id, order_uuid, symbol, volume, price, type, status, created_at 
+        SELECT id, order_uuid, symbol, volume, price, type, kind, limit_price, trigger_price, trailing_offset, 
+        trailing_percent, best_price, filled_volume, reserved, fee, status, time_in_force, expires_at, end_reason, parent_order_id, 
+        group_id, group_leg, triggered_at, filled_at, cancelled_at, created_at, updated_at 
         FROM orders 
         WHERE user_id = $1
         ORDER BY created_at DESC`
@@ -45,28 +56,16 @@ func (c *orderDatabase) GetAllOrders(uid int) ([]utils.OrderResponse, error) {
 		return nil, fmt.Errorf("failed to fetch orders: %w", err)
 	}
 
-	orders := make([]utils.OrderResponse, len(dbOrders))
-	for i, dbOrder := range dbOrders {
-		orders[i] = utils.OrderResponse{
-			OrderID:   dbOrder.ID,
-			OrderUUID: dbOrder.OrderUUID,
-			Symbol:    dbOrder.Symbol,
-			Volume:    dbOrder.Volume,
-			Price:     dbOrder.Price,
-			Type:      dbOrder.Type,
-			Status:    dbOrder.Status,
-			CreatedAt: dbOrder.CreatedAt,
-		}
-	}
-
-	return orders, nil
+	return toOrderResponses(dbOrders), nil
 }
 
 func (c *orderDatabase) GetOrderByID(oid, uid uint) (utils.Order, error) {
 	var order utils.Order
 
 	query := `
-        SELECT id, order_uuid, symbol, volume, price, type, status, created_at 
+        SELECT id, order_uuid, symbol, volume, price, type, kind, limit_price, trigger_price, trailing_offset, 
+        trailing_percent, best_price, filled_volume, reserved, fee, status, time_in_force, expires_at, end_reason, parent_order_id, 
+        group_id, group_leg, triggered_at, filled_at, cancelled_at, created_at, updated_at 
         FROM orders 
         WHERE user_id = $1 AND id = $2
         LIMIT 1`
@@ -84,9 +83,259 @@ func (c *orderDatabase) GetOrderByID(oid, uid uint) (utils.Order, error) {
 	return order, nil
 }
 
-func (c *orderDatabase) DeleteOrderById(oid, uid uint) error {
-	query := `DELETE FROM orders WHERE user_id = $1 AND id = $2`
+// orders placed by the order when it was triggered
+func (c *orderDatabase) GetChildOrders(ctx context.Context, oid uint) ([]utils.Order, error) {
+	var orders []utils.Order
+
+	query := `
+        SELECT id, order_uuid, symbol, volume, price, type, kind, limit_price, trigger_price, trailing_offset, 
+        trailing_percent, best_price, filled_volume, reserved, fee, status, time_in_force, expires_at, end_reason, parent_order_id, 
+        group_id, group_leg, triggered_at, filled_at, cancelled_at, created_at, updated_at 
+        FROM orders 
+        WHERE parent_order_id = $1
+        ORDER BY created_at, id`
+
+	err := conn(c.DB, ctx).Raw(query, oid).Scan(&orders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch child orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (c *orderDatabase) GetOrder(ctx context.Context, oid, uid uint) (domain.Order, error) {
+	var order domain.Order
+
+	query := `SELECT * FROM orders WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+	result := conn(c.DB, ctx).Raw(query, oid, uid).Scan(&order)
+	if result.Error != nil {
+		return domain.Order{}, fmt.Errorf("failed to fetch order: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.Order{}, fmt.Errorf("order not found with ID: %d", oid)
+	}
+	return order, nil
+}
+
+// open and partially filled limit orders and pending trigger orders of all the users, in time priority
+func (c *orderDatabase) GetActiveOrders(ctx context.Context) ([]domain.Order, error) {
+	var orders []domain.Order
 
-	err := c.DB.Exec(query, uid, oid).Error
-	return err
+	query := `
+        SELECT * FROM orders 
+        WHERE (kind = $1 AND status IN ($2, $3)) OR status = $4
+        ORDER BY created_at, id`
+
+	err := conn(c.DB, ctx).Raw(query, domain.OrderKindLimit, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled,
+		domain.OrderStatusPending).Scan(&orders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch open orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (c *orderDatabase) GetLiveOrders(ctx context.Context, uid uint) ([]domain.Order, error) {
+	var orders []domain.Order
+
+	query := `
+        SELECT * FROM orders
+        WHERE user_id = $1 AND status IN ($2, $3, $4)
+        ORDER BY created_at, id`
+
+	err := conn(c.DB, ctx).Raw(query, uid,
+		domain.OrderStatusPending, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled).Scan(&orders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch live orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (c *orderDatabase) CountLiveOrders(ctx context.Context, uid uint) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM orders WHERE user_id = $1 AND status IN ($2, $3, $4)`
+
+	err := conn(c.DB, ctx).Raw(query, uid,
+		domain.OrderStatusPending, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled).Scan(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count live orders: %w", err)
+	}
+	return count, nil
+}
+
+// save the fill state of the order if it's still open with the previous filled volume,
+// return false when the order changed meanwhile
+func (c *orderDatabase) UpdateOrderFill(ctx context.Context, order domain.Order, prevFilledVolume float64) (bool, error) {
+	query := `
+        UPDATE orders SET filled_volume = $1, price = $2, reserved = $3, fee = $4, status = $5, end_reason = $6, 
+        filled_at = $7, updated_at = $8 
+        WHERE id = $9 AND filled_volume = $10 AND status IN ($11, $12)`
+
+	result := conn(c.DB, ctx).Exec(query, order.FilledVolume, order.Price, order.Reserved, order.Fee, order.Status, order.EndReason,
+		order.FilledAt, time.Now(), order.ID, prevFilledVolume, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update order fill: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// mark the pending order as triggered, its reserved funds move to the order it place.
+// return false when it's no longer pending
+func (c *orderDatabase) TriggerOrder(ctx context.Context, oid uint, triggeredAt time.Time) (bool, error) {
+	query := `
+        UPDATE orders SET status = $1, end_reason = $2, reserved = 0, triggered_at = $3, updated_at = $3 
+        WHERE id = $4 AND status = $5`
+
+	result := conn(c.DB, ctx).Exec(query, domain.OrderStatusTriggered, domain.OrderEndTriggered, triggeredAt, oid, domain.OrderStatusPending)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to trigger order: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func toOrderResponses(dbOrders []utils.Order) []utils.OrderResponse {
+	orders := make([]utils.OrderResponse, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		orders[i] = utils.OrderResponse{
+			OrderID:         dbOrder.ID,
+			OrderUUID:       dbOrder.OrderUUID,
+			Symbol:          dbOrder.Symbol,
+			Volume:          dbOrder.Volume,
+			Price:           dbOrder.Price,
+			Type:            dbOrder.Type,
+			Kind:            dbOrder.Kind,
+			LimitPrice:      dbOrder.LimitPrice,
+			TriggerPrice:    dbOrder.TriggerPrice,
+			TrailingOffset:  dbOrder.TrailingOffset,
+			TrailingPercent: dbOrder.TrailingPercent,
+			BestPrice:       dbOrder.BestPrice,
+			FilledVolume:    dbOrder.FilledVolume,
+			Reserved:        dbOrder.Reserved,
+			Fee:             dbOrder.Fee,
+			Status:          dbOrder.Status,
+			TimeInForce:     dbOrder.TimeInForce,
+			ExpiresAt:       dbOrder.ExpiresAt,
+			EndReason:       dbOrder.EndReason,
+			ParentID:        dbOrder.ParentID,
+			GroupID:         dbOrder.GroupID,
+			GroupLeg:        dbOrder.GroupLeg,
+			TriggeredAt:     dbOrder.TriggeredAt,
+			FilledAt:        dbOrder.FilledAt,
+			CancelledAt:     dbOrder.CancelledAt,
+			CreatedAt:       dbOrder.CreatedAt,
+			UpdatedAt:       dbOrder.UpdatedAt,
+		}
+	}
+	return orders
+}
+
+// save the best price and trigger of the pending trailing stop
+func (c *orderDatabase) UpdateTrailingStop(ctx context.Context, oid uint, bestPrice, triggerPrice float64) error {
+	query := `
+        UPDATE orders SET best_price = $1, trigger_price = $2, updated_at = $3 
+        WHERE id = $4 AND status = $5`
+
+	err := conn(c.DB, ctx).Exec(query, bestPrice, triggerPrice, time.Now(), oid, domain.OrderStatusPending).Error
+	if err != nil {
+		return fmt.Errorf("failed to update trailing stop: %w", err)
+	}
+	return nil
+}
+
+// cancel the order if it's still live and clear its reserved funds, return false when it already ended
+func (c *orderDatabase) CancelOrder(ctx context.Context, oid uint, reason string) (bool, error) {
+	query := `
+        UPDATE orders SET status = $1, end_reason = $2, reserved = 0, cancelled_at = $3, updated_at = $3 
+        WHERE id = $4 AND status IN ($5, $6, $7)`
+
+	result := conn(c.DB, ctx).Exec(query, domain.OrderStatusCancelled, reason, time.Now(), oid,
+		domain.OrderStatusPending, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to cancel order: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// live good till date orders whose expiry is at or before now
+func (c *orderDatabase) GetExpiredOrders(ctx context.Context, now time.Time) ([]domain.Order, error) {
+	var orders []domain.Order
+
+	query := `
+        SELECT * FROM orders 
+        WHERE time_in_force = $1 AND expires_at <= $2 AND status IN ($3, $4, $5)
+        ORDER BY expires_at, id`
+
+	err := conn(c.DB, ctx).Raw(query, domain.TimeInForceGTD, now,
+		domain.OrderStatusPending, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled).Scan(&orders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch expired orders: %w", err)
+	}
+	return orders, nil
+}
+
+// expire the order if it's still live and clear its reserved funds, return false when it already ended
+func (c *orderDatabase) ExpireOrder(ctx context.Context, oid uint) (bool, error) {
+	query := `
+        UPDATE orders SET status = $1, end_reason = $2, reserved = 0, updated_at = $3 
+        WHERE id = $4 AND status IN ($5, $6, $7)`
+
+	result := conn(c.DB, ctx).Exec(query, domain.OrderStatusExpired, domain.OrderEndExpired, time.Now(), oid,
+		domain.OrderStatusPending, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to expire order: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// save the new quantity, prices and reserved funds of the order with the amendment in one transaction,
+// return false when the order ended or filled meanwhile
+func (c *orderDatabase) AmendOrder(ctx context.Context, order domain.Order, amendment domain.OrderAmendment) (bool, error) {
+	var amended bool
+
+	err := transaction(c.DB, ctx, func(ctx context.Context) error {
+		tx := conn(c.DB, ctx)
+		now := time.Now()
+
+		query := `
+        UPDATE orders SET volume = $1, limit_price = $2, trigger_price = $3, reserved = $4, updated_at = $5 
+        WHERE id = $6 AND filled_volume = $7 AND status IN ($8, $9, $10)`
+
+		result := tx.Exec(query, order.Volume, order.LimitPrice, order.TriggerPrice, order.Reserved, now, order.ID,
+			order.FilledVolume, domain.OrderStatusPending, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		amended = true
+
+		query = `
+        INSERT INTO order_amendments (order_id, user_id, old_volume, new_volume, old_limit_price, new_limit_price, 
+        old_trigger_price, new_trigger_price, created_at) 
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+		return tx.Exec(query, order.ID, order.UserID, amendment.OldVolume, amendment.NewVolume, amendment.OldLimitPrice,
+			amendment.NewLimitPrice, amendment.OldTriggerPrice, amendment.NewTriggerPrice, now).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to amend order: %w", err)
+	}
+	return amended, nil
+}
+
+func (c *orderDatabase) GetOrderAmendments(ctx context.Context, oid uint) ([]utils.OrderAmendment, error) {
+	var amendments []utils.OrderAmendment
+
+	query := `
+        SELECT id, old_volume, new_volume, old_limit_price, new_limit_price, old_trigger_price, new_trigger_price, created_at 
+        FROM order_amendments 
+        WHERE order_id = $1 
+        ORDER BY created_at, id`
+
+	err := conn(c.DB, ctx).Raw(query, oid).Scan(&amendments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order amendments: %w", err)
+	}
+	return amendments, nil
 }
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+func (c *orderDatabase) CreateOrderGroup(ctx context.Context, group domain.OrderGroup) (uint, error) {
+	var gid uint
+	query := `INSERT INTO order_groups (group_uuid, user_id, type, symbol, side, volume, take_profit_price, stop_price,
+	stop_limit_price, status, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11) RETURNING id`
+
+	err := conn(c.DB, ctx).Raw(query, group.GroupUUID, group.UserID, group.Type, group.Symbol, group.Side, group.Volume,
+		group.TakeProfitPrice, group.StopPrice, group.StopLimitPrice, group.Status, time.Now()).Scan(&gid).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to create order group: %w", err)
+	}
+	return gid, nil
+}
+
+func (c *orderDatabase) GetOrderGroup(ctx context.Context, gid, uid uint) (domain.OrderGroup, error) {
+	var group domain.OrderGroup
+
+	query := `SELECT * FROM order_groups WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+	result := conn(c.DB, ctx).Raw(query, gid, uid).Scan(&group)
+	if result.Error != nil {
+		return domain.OrderGroup{}, fmt.Errorf("failed to fetch order group: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.OrderGroup{}, fmt.Errorf("order group not found with ID: %d", gid)
+	}
+	return group, nil
+}
+
+func (c *orderDatabase) GetOrderGroups(ctx context.Context, uid uint) ([]domain.OrderGroup, error) {
+	var groups []domain.OrderGroup
+
+	query := `SELECT * FROM order_groups WHERE user_id = $1 ORDER BY created_at DESC`
+
+	err := conn(c.DB, ctx).Raw(query, uid).Scan(&groups).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order groups: %w", err)
+	}
+	return groups, nil
+}
+
+// orders of the group, in the order they were placed
+func (c *orderDatabase) GetGroupOrders(ctx context.Context, gid uint) ([]utils.OrderResponse, error) {
+	var dbOrders []utils.Order
+
+	query := `
+        SELECT id, order_uuid, symbol, volume, price, type, kind, limit_price, trigger_price, trailing_offset,
+        trailing_percent, best_price, filled_volume, reserved, fee, status, time_in_force, expires_at, end_reason, parent_order_id,
+        group_id, group_leg, triggered_at, filled_at, cancelled_at, created_at, updated_at
+        FROM orders
+        WHERE group_id = $1
+        ORDER BY created_at, id`
+
+	err := conn(c.DB, ctx).Raw(query, gid).Scan(&dbOrders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group orders: %w", err)
+	}
+	return toOrderResponses(dbOrders), nil
+}
+
+// live orders of the group, locked until the end of the transaction
+func (c *orderDatabase) GetLiveGroupOrders(ctx context.Context, gid uint) ([]domain.Order, error) {
+	var orders []domain.Order
+
+	query := `
+        SELECT * FROM orders
+        WHERE group_id = $1 AND status IN ($2, $3, $4)
+        ORDER BY created_at, id
+        FOR UPDATE`
+
+	err := conn(c.DB, ctx).Raw(query, gid,
+		domain.OrderStatusPending, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled).Scan(&orders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group orders: %w", err)
+	}
+	return orders, nil
+}
+
+// complete the group with the exit leg, return false when another leg completed the group first.
+// the leg which already completed the group can claim it again for its next fills.
+func (c *orderDatabase) ClaimOrderGroup(ctx context.Context, gid, oid uint) (bool, error) {
+	query := `
+        UPDATE order_groups SET status = $1, filled_order_id = $2, updated_at = $3
+        WHERE id = $4 AND (status = $5 OR filled_order_id = $2)`
+
+	result := conn(c.DB, ctx).Exec(query, domain.OrderGroupStatusCompleted, oid, time.Now(), gid, domain.OrderGroupStatusActive)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to complete order group: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// cancel the active group, return false when the group is no longer active
+func (c *orderDatabase) CancelOrderGroup(ctx context.Context, gid, uid uint) (bool, error) {
+	query := `
+        UPDATE order_groups SET status = $1, updated_at = $2
+        WHERE id = $3 AND user_id = $4 AND status = $5`
+
+	result := conn(c.DB, ctx).Exec(query, domain.OrderGroupStatusCancelled, time.Now(), gid, uid, domain.OrderGroupStatusActive)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to cancel order group: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
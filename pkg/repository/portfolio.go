@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"gorm.io/gorm"
+)
+
+type portfolioDatabase struct {
+	DB *gorm.DB
+}
+
+func NewPortfolioRepository(DB *gorm.DB) interfaces.PortfolioRepository {
+	return &portfolioDatabase{DB: DB}
+}
+
+func (c *portfolioDatabase) CreateEquitySnapshots(ctx context.Context, snapshots []domain.EquitySnapshot) error {
+	query := `
+        INSERT INTO equity_snapshots (user_id, cash, positions_value, equity, taken_at)
+        VALUES ($1, $2, $3, $4, $5)`
+
+	return transaction(c.DB, ctx, func(ctx context.Context) error {
+		for _, snapshot := range snapshots {
+			err := conn(c.DB, ctx).Exec(query, snapshot.UserID, snapshot.Cash, snapshot.PositionsValue,
+				snapshot.Equity, snapshot.TakenAt).Error
+			if err != nil {
+				return fmt.Errorf("failed to save equity snapshot: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (c *portfolioDatabase) GetEquitySnapshots(ctx context.Context, uid uint, from, to time.Time) ([]domain.EquitySnapshot, error) {
+	var snapshots []domain.EquitySnapshot
+
+	query := `
+        SELECT * FROM equity_snapshots
+        WHERE user_id = $1 AND taken_at >= $2 AND taken_at <= $3
+        ORDER BY taken_at`
+
+	err := conn(c.DB, ctx).Raw(query, uid, from, to).Scan(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch equity snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+func (c *portfolioDatabase) GetFirstEquitySnapshot(ctx context.Context, uid uint, since time.Time) (domain.EquitySnapshot, error) {
+	var snapshot domain.EquitySnapshot
+
+	query := `
+        SELECT * FROM equity_snapshots
+        WHERE user_id = $1 AND taken_at >= $2
+        ORDER BY taken_at
+        LIMIT 1`
+
+	err := conn(c.DB, ctx).Raw(query, uid, since).Scan(&snapshot).Error
+	if err != nil {
+		return domain.EquitySnapshot{}, fmt.Errorf("failed to fetch equity snapshot: %w", err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type riskDatabase struct {
+	DB *gorm.DB
+}
+
+func NewRiskRepository(DB *gorm.DB) interfaces.RiskRepository {
+	return &riskDatabase{DB: DB}
+}
+
+// key space of the advisory locks of the risk checks, the key in it is the user id
+const riskLockSpace = 1
+
+func (c *riskDatabase) LockUser(ctx context.Context, uid uint) error {
+
+	// a transaction level lock, released on commit or rollback
+	err := conn(c.DB, ctx).Exec(`SELECT pg_advisory_xact_lock($1, $2)`, riskLockSpace, int32(uid)).Error
+	if err != nil {
+		return fmt.Errorf("failed to lock the risk of user %d: %w", uid, err)
+	}
+	return nil
+}
+
+func (c *riskDatabase) GetRiskLimits(ctx context.Context, uid uint) (domain.RiskLimits, error) {
+	var limits domain.RiskLimits
+
+	query := `SELECT * FROM risk_limits WHERE user_id = $1 LIMIT 1`
+
+	err := conn(c.DB, ctx).Raw(query, uid).Scan(&limits).Error
+	if err != nil {
+		return domain.RiskLimits{}, fmt.Errorf("failed to fetch risk limits: %w", err)
+	}
+	return limits, nil
+}
+
+func (c *riskDatabase) SaveRiskLimits(ctx context.Context, limits domain.RiskLimits) error {
+	query := `
+        INSERT INTO risk_limits (user_id, max_order_notional, max_position_size, max_open_orders, max_daily_volume,
+        price_band, max_daily_loss, flatten_on_stop_out, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        ON CONFLICT (user_id) DO UPDATE
+        SET max_order_notional = EXCLUDED.max_order_notional, max_position_size = EXCLUDED.max_position_size,
+        max_open_orders = EXCLUDED.max_open_orders, max_daily_volume = EXCLUDED.max_daily_volume,
+        price_band = EXCLUDED.price_band, max_daily_loss = EXCLUDED.max_daily_loss,
+        flatten_on_stop_out = EXCLUDED.flatten_on_stop_out, updated_at = EXCLUDED.updated_at`
+
+	err := conn(c.DB, ctx).Exec(query, limits.UserID, limits.MaxOrderNotional, limits.MaxPositionSize,
+		limits.MaxOpenOrders, limits.MaxDailyVolume, limits.PriceBand, limits.MaxDailyLoss, limits.FlattenOnStopOut,
+		time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to save risk limits: %w", err)
+	}
+	return nil
+}
+
+func (c *riskDatabase) CreateStopOut(ctx context.Context, stopOut domain.StopOut) (uint, error) {
+	var id uint
+
+	query := `
+        INSERT INTO stop_outs (user_id, trading_day, loss, loss_limit, realized_pnl, unrealized_pnl, flatten, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id`
+
+	err := conn(c.DB, ctx).Raw(query, stopOut.UserID, stopOut.TradingDay, stopOut.Loss, stopOut.LossLimit,
+		stopOut.RealizedPnl, stopOut.UnrealizedPnl, stopOut.Flatten, time.Now()).Scan(&id).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to create stop out: %w", err)
+	}
+	return id, nil
+}
+
+func (c *riskDatabase) GetStopOut(ctx context.Context, uid uint, tradingDay time.Time) (domain.StopOut, error) {
+	var stopOut domain.StopOut
+
+	query := `SELECT * FROM stop_outs WHERE user_id = $1 AND trading_day = $2 ORDER BY id DESC LIMIT 1`
+
+	err := conn(c.DB, ctx).Raw(query, uid, tradingDay).Scan(&stopOut).Error
+	if err != nil {
+		return domain.StopOut{}, fmt.Errorf("failed to fetch stop out: %w", err)
+	}
+	return stopOut, nil
+}
+
+func (c *riskDatabase) ResetStopOut(ctx context.Context, uid uint, tradingDay time.Time) (bool, error) {
+	query := `UPDATE stop_outs SET reset_at = $1 WHERE user_id = $2 AND trading_day = $3 AND reset_at IS NULL`
+
+	result := conn(c.DB, ctx).Exec(query, time.Now(), uid, tradingDay)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to reset stop out: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (c *riskDatabase) GetStopOuts(ctx context.Context, uid uint) ([]utils.StopOut, error) {
+	var stopOuts []utils.StopOut
+
+	query := `
+        SELECT id, user_id, trading_day, loss, loss_limit, realized_pnl, unrealized_pnl, flatten, reset_at, created_at
+        FROM stop_outs
+        WHERE user_id = $1
+        ORDER BY id DESC`
+
+	err := conn(c.DB, ctx).Raw(query, uid).Scan(&stopOuts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stop outs: %w", err)
+	}
+	return stopOuts, nil
+}
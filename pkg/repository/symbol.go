@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type symbolDatabase struct {
+	DB *gorm.DB
+}
+
+func NewSymbolRepository(DB *gorm.DB) interfaces.SymbolRepository {
+	return &symbolDatabase{DB: DB}
+}
+
+func (c *symbolDatabase) SaveSymbols(ctx context.Context, symbols []domain.Symbol) error {
+	query := `
+        INSERT INTO symbols (symbol, status, base_asset, quote_asset, tick_size, step_size, min_qty, max_qty,
+        min_notional, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (symbol) DO UPDATE
+        SET status = EXCLUDED.status, base_asset = EXCLUDED.base_asset, quote_asset = EXCLUDED.quote_asset,
+        tick_size = EXCLUDED.tick_size, step_size = EXCLUDED.step_size, min_qty = EXCLUDED.min_qty,
+        max_qty = EXCLUDED.max_qty, min_notional = EXCLUDED.min_notional, updated_at = EXCLUDED.updated_at`
+
+	return transaction(c.DB, ctx, func(ctx context.Context) error {
+		now := time.Now()
+		for _, symbol := range symbols {
+			err := conn(c.DB, ctx).Exec(query, symbol.Symbol, symbol.Status, symbol.BaseAsset, symbol.QuoteAsset,
+				symbol.TickSize, symbol.StepSize, symbol.MinQty, symbol.MaxQty, symbol.MinNotional, now).Error
+			if err != nil {
+				return fmt.Errorf("failed to save symbol %s: %w", symbol.Symbol, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (c *symbolDatabase) GetSymbol(ctx context.Context, symbol string) (domain.Symbol, error) {
+	var data domain.Symbol
+
+	query := `SELECT * FROM symbols WHERE symbol = $1 LIMIT 1`
+
+	err := conn(c.DB, ctx).Raw(query, symbol).Scan(&data).Error
+	if err != nil {
+		return domain.Symbol{}, fmt.Errorf("failed to fetch symbol: %w", err)
+	}
+	return data, nil
+}
+
+func (c *symbolDatabase) GetSymbols(ctx context.Context) ([]utils.Symbol, error) {
+	var symbols []utils.Symbol
+
+	query := `
+        SELECT symbol, status, base_asset, quote_asset, tick_size, step_size, min_qty, max_qty, min_notional
+        FROM symbols
+        ORDER BY symbol`
+
+	err := conn(c.DB, ctx).Raw(query).Scan(&symbols).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch symbols: %w", err)
+	}
+	return symbols, nil
+}
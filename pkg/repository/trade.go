@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type tradeDatabase struct {
+	DB *gorm.DB
+}
+
+func NewTradeRepository(DB *gorm.DB) interfaces.TradeRepository {
+	return &tradeDatabase{DB: DB}
+}
+
+func (c *tradeDatabase) CreateTrade(ctx context.Context, trade domain.Trade) (uint, error) {
+	var tid uint
+
+	query := `
+        INSERT INTO trades (user_id, order_id, symbol, volume, price, type, liquidity, fee_rate, fee, fee_asset, timestamp)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING id`
+
+	err := conn(c.DB, ctx).Raw(query, trade.UserID, trade.OrderID, trade.Symbol, trade.Volume, trade.Price,
+		trade.Type, trade.Liquidity, trade.FeeRate, trade.Fee, trade.FeeAsset, trade.Timestamp).Scan(&tid).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to save trade: %w", err)
+	}
+	return tid, nil
+}
+
+func (c *tradeDatabase) SetTradeRealizedPnl(ctx context.Context, tid uint, pnl float64) error {
+	query := `UPDATE trades SET realized_pnl = $1 WHERE id = $2`
+
+	err := conn(c.DB, ctx).Exec(query, pnl, tid).Error
+	if err != nil {
+		return fmt.Errorf("failed to update trade: %w", err)
+	}
+	return nil
+}
+
+// trades of the user, oldest first
+func (c *tradeDatabase) GetUserTrades(ctx context.Context, uid uint) ([]utils.Trade, error) {
+	var trades []utils.Trade
+
+	query := `
+        SELECT id, order_id, volume, price, realized_pnl, liquidity, fee_rate, fee, fee_asset, timestamp
+        FROM trades
+        WHERE user_id = $1
+        ORDER BY timestamp, id`
+
+	err := conn(c.DB, ctx).Raw(query, uid).Scan(&trades).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trades: %w", err)
+	}
+	return trades, nil
+}
+
+// value of the trades of the user since the time, in their quote asset
+func (c *tradeDatabase) GetTradedValue(ctx context.Context, uid uint, since time.Time) (float64, error) {
+	var value float64
+
+	query := `SELECT COALESCE(SUM(volume * price), 0) FROM trades WHERE user_id = $1 AND timestamp >= $2`
+
+	err := conn(c.DB, ctx).Raw(query, uid, since).Scan(&value).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch traded value: %w", err)
+	}
+	return value, nil
+}
+
+func (c *tradeDatabase) GetRealizedPnl(ctx context.Context, uid uint, since time.Time) (float64, error) {
+	var pnl float64
+
+	query := `SELECT COALESCE(SUM(realized_pnl), 0) FROM trades WHERE user_id = $1 AND timestamp >= $2`
+
+	err := conn(c.DB, ctx).Raw(query, uid, since).Scan(&pnl).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch realized pnl: %w", err)
+	}
+	return pnl, nil
+}
+
+func (c *tradeDatabase) GetTradingUsers(ctx context.Context, since time.Time) ([]uint, error) {
+	var users []uint
+
+	query := `
+        SELECT user_id FROM positions WHERE volume <> 0
+        UNION
+        SELECT user_id FROM trades WHERE timestamp >= $1
+        ORDER BY user_id`
+
+	err := conn(c.DB, ctx).Raw(query, since).Scan(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trading users: %w", err)
+	}
+	return users, nil
+}
+
+func (c *tradeDatabase) GetClosingTrades(ctx context.Context, uid uint, from, to time.Time) ([]utils.Trade, error) {
+	var trades []utils.Trade
+
+	query := `
+        SELECT id, order_id, volume, price, realized_pnl, liquidity, fee_rate, fee, fee_asset, timestamp
+        FROM trades t
+        WHERE user_id = $1 AND timestamp >= $2 AND timestamp <= $3
+            AND EXISTS (SELECT 1 FROM lot_matches m WHERE m.trade_id = t.id)
+        ORDER BY timestamp, id`
+
+	err := conn(c.DB, ctx).Raw(query, uid, from, to).Scan(&trades).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch closing trades: %w", err)
+	}
+	return trades, nil
+}
+
+func (c *tradeDatabase) GetPositionForUpdate(ctx context.Context, uid uint, symbol string) (domain.Position, error) {
+	position := domain.Position{UserID: uid, Symbol: symbol}
+
+	query := `SELECT * FROM positions WHERE user_id = $1 AND symbol = $2 LIMIT 1 FOR UPDATE`
+
+	err := conn(c.DB, ctx).Raw(query, uid, symbol).Scan(&position).Error
+	if err != nil {
+		return domain.Position{}, fmt.Errorf("failed to fetch position: %w", err)
+	}
+	return position, nil
+}
+
+func (c *tradeDatabase) SavePosition(ctx context.Context, position domain.Position) error {
+	query := `
+        INSERT INTO positions (user_id, symbol, volume, entry_price, unrealized_pnl, realized_pnl, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, 0, $5, $6, $6)
+        ON CONFLICT (user_id, symbol) DO UPDATE
+        SET volume = EXCLUDED.volume, entry_price = EXCLUDED.entry_price, realized_pnl = EXCLUDED.realized_pnl, updated_at = $6`
+
+	err := conn(c.DB, ctx).Exec(query, position.UserID, position.Symbol, position.Volume, position.EntryPrice,
+		position.RealizedPnl, time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to save position: %w", err)
+	}
+	return nil
+}
+
+// positions of the user with some volume left, by symbol
+func (c *tradeDatabase) GetOpenPositions(ctx context.Context, uid uint) ([]domain.Position, error) {
+	var positions []domain.Position
+
+	query := `SELECT * FROM positions WHERE user_id = $1 AND volume <> 0 ORDER BY symbol`
+
+	err := conn(c.DB, ctx).Raw(query, uid).Scan(&positions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch positions: %w", err)
+	}
+	return positions, nil
+}
+
+func (c *tradeDatabase) GetOpenLots(ctx context.Context, uid uint, symbol string) ([]domain.TaxLot, error) {
+	var lots []domain.TaxLot
+
+	query := `
+        SELECT * FROM tax_lots
+        WHERE user_id = $1 AND symbol = $2 AND remaining > 0
+        ORDER BY opened_at, id
+        FOR UPDATE`
+
+	err := conn(c.DB, ctx).Raw(query, uid, symbol).Scan(&lots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tax lots: %w", err)
+	}
+	return lots, nil
+}
+
+func (c *tradeDatabase) CreateLot(ctx context.Context, lot domain.TaxLot) (uint, error) {
+	var lid uint
+
+	query := `
+        INSERT INTO tax_lots (user_id, symbol, trade_id, side, volume, remaining, price, opened_at, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+        RETURNING id`
+
+	err := conn(c.DB, ctx).Raw(query, lot.UserID, lot.Symbol, lot.TradeID, lot.Side, lot.Volume, lot.Remaining,
+		lot.Price, lot.OpenedAt, time.Now()).Scan(&lid).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to save tax lot: %w", err)
+	}
+	return lid, nil
+}
+
+func (c *tradeDatabase) UpdateLotRemaining(ctx context.Context, lid uint, remaining float64) error {
+	query := `UPDATE tax_lots SET remaining = $1, updated_at = $2 WHERE id = $3`
+
+	err := conn(c.DB, ctx).Exec(query, remaining, time.Now(), lid).Error
+	if err != nil {
+		return fmt.Errorf("failed to update tax lot: %w", err)
+	}
+	return nil
+}
+
+func (c *tradeDatabase) CreateLotMatch(ctx context.Context, match domain.LotMatch) error {
+	query := `
+        INSERT INTO lot_matches (user_id, trade_id, lot_id, method, volume, open_price, close_price, realized_pnl, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	err := conn(c.DB, ctx).Exec(query, match.UserID, match.TradeID, match.LotID, match.Method, match.Volume,
+		match.OpenPrice, match.ClosePrice, match.RealizedPnl, time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to save lot match: %w", err)
+	}
+	return nil
+}
+
+// lots closed by the trades of the user with the time they were opened
+func (c *tradeDatabase) GetUserLotMatches(ctx context.Context, uid uint) ([]utils.LotMatch, error) {
+	var matches []utils.LotMatch
+
+	query := `
+        SELECT m.lot_id, m.trade_id, m.method, m.volume, m.open_price, m.close_price, m.realized_pnl, l.opened_at
+        FROM lot_matches m
+        JOIN tax_lots l ON l.id = m.lot_id
+        WHERE m.user_id = $1
+        ORDER BY m.id`
+
+	err := conn(c.DB, ctx).Raw(query, uid).Scan(&matches).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lot matches: %w", err)
+	}
+	return matches, nil
+}
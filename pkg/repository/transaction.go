@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// the database transaction of the ctx
+type txKey struct{}
+
+// run fn in a database transaction carried by its ctx, so the repositories called
+// with that ctx share it. fn joins the transaction of the ctx if there is one already.
+func transaction(DB *gorm.DB, ctx context.Context, fn func(ctx context.Context) error) error {
+
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// connection of the ctx, its transaction if any
+func conn(DB *gorm.DB, ctx context.Context) *gorm.DB {
+
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return DB
+}
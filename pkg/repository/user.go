@@ -18,6 +18,10 @@ func NewUserRepository(DB *gorm.DB) interfaces.UserRepository {
 	return &userDatabase{DB: DB}
 }
 
+func (c *userDatabase) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return transaction(c.DB, ctx, fn)
+}
+
 func (c *userDatabase) FindUserByUserID(ctx context.Context, userID uint) (user domain.User, err error) {
 
 	query := `SELECT * FROM users WHERE id = $1`
@@ -40,7 +44,7 @@ func (c *userDatabase) SaveUser(ctx context.Context, user request.RegisterUserRe
 	VALUES ($1, $2, $3, $4 ) RETURNING id`
 
 	createdAt := time.Now()
-	err = c.DB.Raw(query, user.Username, user.Email, user.Password, createdAt).Scan(&userID).Error
+	err = conn(c.DB, ctx).Raw(query, user.Username, user.Email, user.Password, createdAt).Scan(&userID).Error
 
 	return userID, err
 }
@@ -59,3 +63,15 @@ func (c *userDatabase) GetUserId(ctx context.Context, email string) (int, error)
 	err := c.DB.Raw(query, email).Scan(&userId).Error
 	return userId, err
 }
+
+func (c *userDatabase) GetCostBasisMethod(ctx context.Context, userID uint) (string, error) {
+	var method string
+	query := `SELECT cost_basis_method FROM users WHERE id = $1`
+	err := conn(c.DB, ctx).Raw(query, userID).Scan(&method).Error
+	return method, err
+}
+
+func (c *userDatabase) SetCostBasisMethod(ctx context.Context, userID uint, method string) error {
+	query := `UPDATE users SET cost_basis_method = $1, updated_at = $2 WHERE id = $3`
+	return conn(c.DB, ctx).Exec(query, method, time.Now(), userID).Error
+}
@@ -0,0 +1,148 @@
+package marketdata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+)
+
+type binanceProvider struct {
+	restURL     string
+	wsURL       string
+	client      *http.Client
+	depthLevels int
+}
+
+// NewBinanceProvider create a provider backed by the Binance REST and WebSocket api
+func NewBinanceProvider(cfg config.Config) MarketDataProvider {
+	return &binanceProvider{
+		restURL: strings.TrimSuffix(cfg.BinanceRestURL, "/"),
+		wsURL:   strings.TrimSuffix(cfg.BinanceWsURL, "/"),
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		depthLevels: cfg.MarketDepthLevels,
+	}
+}
+
+func (c *binanceProvider) GetQuote(ctx context.Context, symbol string) (Quote, error) {
+
+	url := fmt.Sprintf("%s/api/v3/ticker/bookTicker?symbol=%s", c.restURL, symbol)
+
+	var data Quote
+	if err := c.get(ctx, url, &data); err != nil {
+		return Quote{}, err
+	}
+
+	if data.BidPrice == 0 || data.AskPrice == 0 {
+		return Quote{}, fmt.Errorf("received invalid price data for symbol %s: bid=%v, ask=%v",
+			symbol, data.BidPrice, data.AskPrice)
+	}
+
+	return data, nil
+}
+
+func (c *binanceProvider) GetDepth(ctx context.Context, symbol string) (Depth, error) {
+
+	url := fmt.Sprintf("%s/api/v3/depth?symbol=%s&limit=%d", c.restURL, symbol, c.depthLevels)
+
+	var data struct {
+		Bids depthLevels `json:"bids"`
+		Asks depthLevels `json:"asks"`
+	}
+	if err := c.get(ctx, url, &data); err != nil {
+		return Depth{}, err
+	}
+
+	if len(data.Bids) == 0 || len(data.Asks) == 0 {
+		return Depth{}, fmt.Errorf("received empty order book for symbol %s", symbol)
+	}
+
+	return Depth{Symbol: symbol, Bids: data.Bids, Asks: data.Asks}, nil
+}
+
+func (c *binanceProvider) ExchangeInfo(ctx context.Context) ([]SymbolInfo, error) {
+
+	url := fmt.Sprintf("%s/api/v3/exchangeInfo?permissions=SPOT", c.restURL)
+
+	var exchangeInfo struct {
+		Symbols []SymbolInfo `json:"symbols"`
+	}
+	if err := c.get(ctx, url, &exchangeInfo); err != nil {
+		return nil, err
+	}
+	return exchangeInfo.Symbols, nil
+}
+
+func (c *binanceProvider) Stream(ctx context.Context, symbol string, kind StreamKind) (Stream, error) {
+
+	url := fmt.Sprintf("%s/ws/%s@%s", c.wsURL, strings.ToLower(symbol), kind)
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Binance WebSocket: %w", err)
+	}
+
+	return &binanceStream{conn: conn}, nil
+}
+
+// call the Binance REST api and decode the response body into data
+func (c *binanceProvider) get(ctx context.Context, url string, data interface{}) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch market data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var binanceError struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		if err := json.Unmarshal(bodyBytes, &binanceError); err != nil {
+			return fmt.Errorf("API error: %s", string(bodyBytes))
+		}
+		return fmt.Errorf("Binance API error: %s (code: %d)",
+			binanceError.Msg, binanceError.Code)
+	}
+
+	if err := json.Unmarshal(bodyBytes, data); err != nil {
+		return fmt.Errorf("failed to decode market data: %w, raw data: %s",
+			err, string(bodyBytes))
+	}
+	return nil
+}
+
+type binanceStream struct {
+	conn *websocket.Conn
+}
+
+func (s *binanceStream) Read() ([]byte, error) {
+	_, message, err := s.conn.ReadMessage()
+	if err != nil {
+		return nil, fmt.Errorf("error reading Binance WebSocket message: %w", err)
+	}
+	return message, nil
+}
+
+func (s *binanceStream) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,84 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Depth is a snapshot of the order book of a symbol, the bids from the highest price
+// and the asks from the lowest
+type Depth struct {
+	Symbol string
+	Bids   []DepthLevel
+	Asks   []DepthLevel
+}
+
+type DepthLevel struct {
+	Price float64
+	Qty   float64
+}
+
+// best bid/ask of the depth
+func (d Depth) Quote() Quote {
+
+	quote := Quote{Symbol: d.Symbol}
+	if len(d.Bids) > 0 {
+		quote.BidPrice, quote.BidQty = d.Bids[0].Price, d.Bids[0].Qty
+	}
+	if len(d.Asks) > 0 {
+		quote.AskPrice, quote.AskQty = d.Asks[0].Price, d.Asks[0].Qty
+	}
+	return quote
+}
+
+// depth curve of the feeds without an order book, levels on each side of the quote
+// step apart as a fraction of its price. a level hold more than the one before it,
+// the top quantity of the quote grown by depthLevelGrowth per level.
+func depthCurve(quote Quote, levels int, step float64) Depth {
+
+	depth := Depth{
+		Symbol: quote.Symbol,
+		Bids:   make([]DepthLevel, 0, levels),
+		Asks:   make([]DepthLevel, 0, levels),
+	}
+
+	for i := 0; i < levels; i++ {
+		growth := 1 + depthLevelGrowth*float64(i)
+		if bid := quote.BidPrice * (1 - step*float64(i)); bid > 0 {
+			depth.Bids = append(depth.Bids, DepthLevel{Price: bid, Qty: quote.BidQty * growth})
+		}
+		depth.Asks = append(depth.Asks, DepthLevel{Price: quote.AskPrice * (1 + step*float64(i)), Qty: quote.AskQty * growth})
+	}
+	return depth
+}
+
+// quantity added to each deeper level of a depth curve, as a fraction of the top quantity
+const depthLevelGrowth = 0.5
+
+// depthLevels decode the Binance depth levels, [price, qty] pairs of strings
+type depthLevels []DepthLevel
+
+func (l *depthLevels) UnmarshalJSON(data []byte) error {
+
+	var pairs [][2]string
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return err
+	}
+
+	levels := make(depthLevels, len(pairs))
+	for i, pair := range pairs {
+		price, err := strconv.ParseFloat(pair[0], 64)
+		if err != nil {
+			return fmt.Errorf("invalid depth price %q: %w", pair[0], err)
+		}
+		qty, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			return fmt.Errorf("invalid depth quantity %q: %w", pair[1], err)
+		}
+		levels[i] = DepthLevel{Price: price, Qty: qty}
+	}
+
+	*l = levels
+	return nil
+}
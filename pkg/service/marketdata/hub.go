@@ -0,0 +1,402 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+)
+
+const (
+	SlowClientDrop       = "drop"
+	SlowClientDisconnect = "disconnect"
+)
+
+// status of a stream sent to its clients while the provider stream is reconnected
+const (
+	StreamReconnecting = "reconnecting"
+	StreamResumed      = "resumed"
+)
+
+// Hub share one provider stream per symbol and stream kind between all the clients subscribed to it
+type Hub interface {
+	// add a new client to the hub
+	Register() *Client
+	// remove the client from all its subscriptions and close it
+	Unregister(client *Client)
+
+	Subscribe(client *Client, symbol string, kind StreamKind) error
+	Unsubscribe(client *Client, symbol string, kind StreamKind)
+	// stream names the client is subscribed to
+	Subscriptions(client *Client) []string
+
+	// state of the provider streams for monitoring
+	Stats() HubStats
+}
+
+// Message is one message of a provider stream delivered to a client,
+// either the Data of the stream or a Status change of it
+type Message struct {
+	Symbol string
+	Kind   StreamKind
+	Data   []byte
+	Status string
+}
+
+type HubStats struct {
+	Clients    int             `json:"clients"`
+	Reconnects int64           `json:"reconnects"`
+	Streams    []UpstreamStats `json:"streams"`
+}
+
+type UpstreamStats struct {
+	Stream      string    `json:"stream"`
+	Clients     int       `json:"clients"`
+	Connected   bool      `json:"connected"`
+	ConnectedAt time.Time `json:"connectedAt"`
+	Reconnects  int64     `json:"reconnects"`
+	Dropped     int64     `json:"dropped"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// Client is one consumer of the hub with its own bounded buffer of messages
+type Client struct {
+	send      chan Message
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// subscriptions of the client, guarded by the hub lock
+	upstreams map[string]*upstream
+}
+
+// Messages to deliver to the client
+func (c *Client) Messages() <-chan Message {
+	return c.send
+}
+
+// Done is closed once the client is unregistered or disconnected by the hub
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// upstream is the provider stream shared by the clients of a symbol and stream kind,
+// all its fields are guarded by the hub lock
+type upstream struct {
+	symbol  string
+	kind    StreamKind
+	stream  Stream
+	cancel  context.CancelFunc
+	clients map[*Client]bool
+
+	connected   bool
+	connectedAt time.Time
+	reconnects  int64
+	dropped     int64
+	lastError   string
+}
+
+type hub struct {
+	provider   MarketDataProvider
+	bufferSize int
+	slowClient string
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu         sync.Mutex
+	upstreams  map[string]*upstream
+	clients    map[*Client]bool
+	reconnects int64
+}
+
+// NewHub create a hub fanning out the streams of the provider
+func NewHub(provider MarketDataProvider, cfg config.Config) Hub {
+	return &hub{
+		provider:   provider,
+		bufferSize: cfg.MarketHubClientBuffer,
+		slowClient: cfg.MarketHubSlowClient,
+		minBackoff: cfg.MarketReconnectMinBackoff,
+		maxBackoff: cfg.MarketReconnectMaxBackoff,
+		upstreams:  make(map[string]*upstream),
+		clients:    make(map[*Client]bool),
+	}
+}
+
+func (h *hub) Register() *Client {
+	client := &Client{
+		send:      make(chan Message, h.bufferSize),
+		done:      make(chan struct{}),
+		upstreams: make(map[string]*upstream),
+	}
+
+	h.mu.Lock()
+	h.clients[client] = true
+	h.mu.Unlock()
+
+	return client
+}
+
+func (h *hub) Unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key := range client.upstreams {
+		h.removeClient(client, key)
+	}
+	delete(h.clients, client)
+	client.close()
+}
+
+func (h *hub) Subscribe(client *Client, symbol string, kind StreamKind) error {
+	key := streamKey(symbol, kind)
+
+	h.mu.Lock()
+	if u, ok := h.upstreams[key]; ok {
+		h.addClient(client, u, key)
+		h.mu.Unlock()
+		return nil
+	}
+	h.mu.Unlock()
+
+	// open the provider stream without holding the lock, the dial can be slow
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := h.provider.Stream(ctx, symbol, kind)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// another client opened the same stream meanwhile
+	if u, ok := h.upstreams[key]; ok {
+		stream.Close()
+		cancel()
+		h.addClient(client, u, key)
+		return nil
+	}
+
+	u := &upstream{
+		symbol:      symbol,
+		kind:        kind,
+		stream:      stream,
+		cancel:      cancel,
+		clients:     make(map[*Client]bool),
+		connected:   true,
+		connectedAt: time.Now(),
+	}
+	h.upstreams[key] = u
+	h.addClient(client, u, key)
+
+	go h.run(ctx, u, key, stream)
+
+	return nil
+}
+
+func (h *hub) Unsubscribe(client *Client, symbol string, kind StreamKind) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.removeClient(client, streamKey(symbol, kind))
+}
+
+func (h *hub) Subscriptions(client *Client) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	streams := make([]string, 0, len(client.upstreams))
+	for _, u := range client.upstreams {
+		streams = append(streams, StreamName(u.symbol, u.kind))
+	}
+	sort.Strings(streams)
+
+	return streams
+}
+
+func (h *hub) Stats() HubStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats := HubStats{
+		Clients:    len(h.clients),
+		Reconnects: h.reconnects,
+		Streams:    make([]UpstreamStats, 0, len(h.upstreams)),
+	}
+	for _, u := range h.upstreams {
+		stats.Streams = append(stats.Streams, UpstreamStats{
+			Stream:      StreamName(u.symbol, u.kind),
+			Clients:     len(u.clients),
+			Connected:   u.connected,
+			ConnectedAt: u.connectedAt,
+			Reconnects:  u.reconnects,
+			Dropped:     u.dropped,
+			LastError:   u.lastError,
+		})
+	}
+	sort.Slice(stats.Streams, func(i, j int) bool {
+		return stats.Streams[i].Stream < stats.Streams[j].Stream
+	})
+
+	return stats
+}
+
+// read the upstream and fan out its messages until it's torn down,
+// reconnecting the provider stream when it fails
+func (h *hub) run(ctx context.Context, u *upstream, key string, stream Stream) {
+
+	for {
+		data, err := stream.Read()
+		if err == nil {
+			h.broadcast(u, Message{Symbol: u.symbol, Kind: u.kind, Data: data})
+			continue
+		}
+		stream.Close()
+
+		// torn down after the last client left
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("Error reading market data stream %s: %v", key, err)
+
+		// a finished replay has nothing more to send
+		if errors.Is(err, ErrReplayFinished) {
+			h.closeUpstream(u, key)
+			return
+		}
+
+		h.mu.Lock()
+		u.connected = false
+		u.lastError = err.Error()
+		h.mu.Unlock()
+		h.broadcast(u, Message{Symbol: u.symbol, Kind: u.kind, Status: StreamReconnecting})
+
+		if stream = h.reconnect(ctx, u, key); stream == nil {
+			return
+		}
+		h.broadcast(u, Message{Symbol: u.symbol, Kind: u.kind, Status: StreamResumed})
+	}
+}
+
+// open the provider stream again with a jittered exponential backoff,
+// return nil if the upstream is torn down meanwhile
+func (h *hub) reconnect(ctx context.Context, u *upstream, key string) Stream {
+
+	for attempt := 0; ; attempt++ {
+		timer := time.NewTimer(h.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		stream, err := h.provider.Stream(ctx, u.symbol, u.kind)
+
+		h.mu.Lock()
+		u.reconnects++
+		h.reconnects++
+
+		if err != nil {
+			u.lastError = err.Error()
+			h.mu.Unlock()
+			log.Printf("Failed to reconnect market data stream %s (attempt %d): %v", key, attempt+1, err)
+			continue
+		}
+
+		if ctx.Err() != nil {
+			h.mu.Unlock()
+			stream.Close()
+			return nil
+		}
+
+		u.stream = stream
+		u.connected = true
+		u.connectedAt = time.Now()
+		h.mu.Unlock()
+
+		log.Printf("Reconnected market data stream %s", key)
+		return stream
+	}
+}
+
+// backoff before the reconnect attempt, half of it is random
+func (h *hub) backoff(attempt int) time.Duration {
+
+	backoff := h.maxBackoff
+	if attempt < 32 && h.minBackoff<<attempt < h.maxBackoff {
+		backoff = h.minBackoff << attempt
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// deliver the message to every client of the upstream without blocking on slow clients
+func (h *hub) broadcast(u *upstream, message Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range u.clients {
+		select {
+		case client.send <- message:
+		default:
+			u.dropped++
+			if h.slowClient == SlowClientDisconnect {
+				log.Printf("Disconnecting slow market data client")
+				client.close()
+				go h.Unregister(client)
+			}
+		}
+	}
+}
+
+// disconnect all the clients of an upstream which can't continue
+func (h *hub) closeUpstream(u *upstream, key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.upstreams[key] != u {
+		return
+	}
+	delete(h.upstreams, key)
+	u.cancel()
+
+	for client := range u.clients {
+		delete(client.upstreams, key)
+		client.close()
+	}
+}
+
+// must be called with the lock held
+func (h *hub) addClient(client *Client, u *upstream, key string) {
+	u.clients[client] = true
+	client.upstreams[key] = u
+}
+
+// remove the client from the upstream and tear it down if it was the last one,
+// must be called with the lock held
+func (h *hub) removeClient(client *Client, key string) {
+	u, ok := client.upstreams[key]
+	if !ok {
+		return
+	}
+	delete(client.upstreams, key)
+	delete(u.clients, client)
+
+	if len(u.clients) == 0 && h.upstreams[key] == u {
+		delete(h.upstreams, key)
+		u.cancel()
+		u.stream.Close()
+	}
+}
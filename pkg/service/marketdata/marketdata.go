@@ -0,0 +1,141 @@
+package marketdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+)
+
+// MarketDataProvider is the source of prices for the platform. The usecases and
+// handlers only talk to this interface so the feed can be swapped per environment.
+type MarketDataProvider interface {
+	// best bid/ask for the symbol
+	GetQuote(ctx context.Context, symbol string) (Quote, error)
+	// order book of the symbol, the orders are filled across its levels
+	GetDepth(ctx context.Context, symbol string) (Depth, error)
+	// open a live stream of messages for the symbol
+	Stream(ctx context.Context, symbol string, kind StreamKind) (Stream, error)
+	// exchange metadata of every symbol of the feed, loaded into the symbol registry
+	ExchangeInfo(ctx context.Context) ([]SymbolInfo, error)
+}
+
+// Stream is a live feed of raw JSON messages opened on a provider
+type Stream interface {
+	Read() ([]byte, error)
+	Close() error
+}
+
+// StreamKind is the Binance name of a stream, klines are named by interval as "kline_1m"
+type StreamKind string
+
+const (
+	StreamTicker     StreamKind = "ticker"
+	StreamBookTicker StreamKind = "bookTicker"
+	StreamTrade      StreamKind = "trade"
+
+	streamKlinePrefix = "kline_"
+)
+
+type SymbolInfo struct {
+	Symbol     string         `json:"symbol"`
+	Status     string         `json:"status"`
+	BaseAsset  string         `json:"baseAsset"`
+	QuoteAsset string         `json:"quoteAsset"`
+	Filters    []SymbolFilter `json:"filters"`
+}
+
+// SymbolFilter is a Binance exchange filter of a symbol, the fields set depend on the FilterType
+type SymbolFilter struct {
+	FilterType  string `json:"filterType"`
+	TickSize    string `json:"tickSize,omitempty"`
+	StepSize    string `json:"stepSize,omitempty"`
+	MinQty      string `json:"minQty,omitempty"`
+	MaxQty      string `json:"maxQty,omitempty"`
+	MinNotional string `json:"minNotional,omitempty"`
+}
+
+// filters of the symbols the registry use
+const (
+	FilterPrice       = "PRICE_FILTER"
+	FilterLotSize     = "LOT_SIZE"
+	FilterNotional    = "NOTIONAL"
+	FilterMinNotional = "MIN_NOTIONAL"
+)
+
+// status of the symbols open for trading
+const SymbolStatusTrading = "TRADING"
+
+const (
+	ProviderBinance   = "binance"
+	ProviderSynthetic = "synthetic"
+	ProviderReplay    = "replay"
+
+	// quote currency used when the symbol is given without one
+	DefaultQuoteAsset = "USDT"
+)
+
+var ErrUnknownProvider = errors.New("unknown market data provider")
+
+// NewMarketDataProvider create the provider selected on the config,
+// wrapped to record its streams when a record file is configured. the cleanup close the record file
+func NewMarketDataProvider(cfg config.Config) (MarketDataProvider, func(), error) {
+
+	var (
+		provider MarketDataProvider
+		err      error
+	)
+
+	switch cfg.MarketDataProvider {
+	case ProviderBinance, "":
+		provider = NewBinanceProvider(cfg)
+	case ProviderSynthetic:
+		provider, err = NewSyntheticProvider(cfg)
+	case ProviderReplay:
+		provider, err = NewReplayProvider(cfg)
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnknownProvider, cfg.MarketDataProvider)
+	}
+
+	if err != nil || cfg.MarketDataRecordFile == "" {
+		return provider, func() {}, err
+	}
+
+	recorder, err := NewRecordingProvider(provider, cfg.MarketDataRecordFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return recorder, func() {
+		if err := recorder.(io.Closer).Close(); err != nil {
+			log.Printf("Failed to close the market data record file: %v", err)
+		}
+	}, nil
+}
+
+// NormalizeSymbol make the symbol upper case without spaces, the symbol registry resolve it
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.ReplaceAll(symbol, " ", ""))
+}
+
+// LoadExchangeInfo read the symbols of an exchangeInfo style JSON file, a local seed of the symbol registry
+func LoadExchangeInfo(path string) ([]SymbolInfo, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange info file: %w", err)
+	}
+
+	var exchangeInfo struct {
+		Symbols []SymbolInfo `json:"symbols"`
+	}
+	if err := json.Unmarshal(data, &exchangeInfo); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange info file %s: %w", path, err)
+	}
+	return exchangeInfo.Symbols, nil
+}
@@ -0,0 +1,127 @@
+package marketdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Record is one line of a market data recording file
+type Record struct {
+	Time   time.Time       `json:"time"`
+	Symbol string          `json:"symbol"`
+	Stream StreamKind      `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// recordingProvider wrap a provider and write every streamed message to a JSON lines file,
+// until it's closed
+type recordingProvider struct {
+	provider MarketDataProvider
+
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewRecordingProvider wrap the provider to append its streams to the file at path
+func NewRecordingProvider(provider MarketDataProvider, path string) (MarketDataProvider, error) {
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open market data record file: %w", err)
+	}
+
+	return &recordingProvider{
+		provider: provider,
+		file:     file,
+		encoder:  json.NewEncoder(file),
+	}, nil
+}
+
+func (c *recordingProvider) GetQuote(ctx context.Context, symbol string) (Quote, error) {
+	return c.provider.GetQuote(ctx, symbol)
+}
+
+func (c *recordingProvider) GetDepth(ctx context.Context, symbol string) (Depth, error) {
+	return c.provider.GetDepth(ctx, symbol)
+}
+
+func (c *recordingProvider) ExchangeInfo(ctx context.Context) ([]SymbolInfo, error) {
+	return c.provider.ExchangeInfo(ctx)
+}
+
+func (c *recordingProvider) Stream(ctx context.Context, symbol string, kind StreamKind) (Stream, error) {
+
+	stream, err := c.provider.Stream(ctx, symbol, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingStream{
+		Stream:   stream,
+		recorder: c,
+		symbol:   symbol,
+		kind:     kind,
+	}, nil
+}
+
+// append the message to the record file
+func (c *recordingProvider) record(symbol string, kind StreamKind, message []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return errors.New("market data record file is closed")
+	}
+	return c.encoder.Encode(Record{
+		Time:   time.Now().UTC(),
+		Symbol: symbol,
+		Stream: kind,
+		Data:   message,
+	})
+}
+
+// Close flush the record file to disk and close it, the streams still open stop recording
+func (c *recordingProvider) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil
+	}
+	file := c.file
+	c.file, c.encoder = nil, nil
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to flush market data record file: %w", err)
+	}
+	return file.Close()
+}
+
+type recordingStream struct {
+	Stream
+	recorder *recordingProvider
+	symbol   string
+	kind     StreamKind
+}
+
+func (s *recordingStream) Read() ([]byte, error) {
+
+	message, err := s.Stream.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	// a failed write should not break the live stream
+	if err := s.recorder.record(s.symbol, s.kind, message); err != nil {
+		log.Printf("Failed to record market data message: %v", err)
+	}
+	return message, nil
+}
@@ -0,0 +1,269 @@
+package marketdata
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+)
+
+var ErrReplayFinished = errors.New("market data replay finished")
+
+// recorded streams carrying the best bid/ask
+var quoteStreams = []StreamKind{StreamTicker, StreamBookTicker}
+
+// replayProvider serve quotes and streams from a recording file. All of them follow one
+// replay clock which start at the first record when the provider is created and run at speed.
+type replayProvider struct {
+	speed     float64
+	startedAt time.Time
+	firstTime time.Time
+
+	// the recordings have no order book, the depth is a curve around the quote
+	depthLevels int
+	depthStep   float64
+
+	// records of each symbol and stream ordered by time
+	records map[string][]Record
+}
+
+// NewReplayProvider load the recording file of the config and replay it
+func NewReplayProvider(cfg config.Config) (MarketDataProvider, error) {
+
+	records, err := loadRecords(cfg.MarketDataReplayFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("market data replay file %s has no records", cfg.MarketDataReplayFile)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+
+	provider := &replayProvider{
+		speed:       cfg.MarketDataReplaySpeed,
+		startedAt:   time.Now(),
+		firstTime:   records[0].Time,
+		depthLevels: cfg.MarketDepthLevels,
+		depthStep:   cfg.MarketDepthStep,
+		records:     make(map[string][]Record),
+	}
+
+	for _, record := range records {
+		key := streamKey(record.Symbol, record.Stream)
+		provider.records[key] = append(provider.records[key], record)
+	}
+
+	return provider, nil
+}
+
+// GetQuote return the bid/ask of the latest record of the symbol at the replay clock
+func (c *replayProvider) GetQuote(ctx context.Context, symbol string) (Quote, error) {
+	return c.quoteAt(symbol, c.replayTime(time.Now()))
+}
+
+func (c *replayProvider) quoteAt(symbol string, now time.Time) (Quote, error) {
+
+	var (
+		latest   Record
+		found    bool
+		recorded bool
+	)
+	for _, kind := range quoteStreams {
+		records, ok := c.records[streamKey(symbol, kind)]
+		if !ok || len(records) == 0 {
+			continue
+		}
+		recorded = true
+
+		// first record after the replay clock, the one before it is the current
+		i := sort.Search(len(records), func(i int) bool {
+			return records[i].Time.After(now)
+		})
+		// the stream has no record yet, its first one is in the future of the replay
+		if i == 0 {
+			continue
+		}
+		i--
+
+		if !found || records[i].Time.After(latest.Time) {
+			latest, found = records[i], true
+		}
+	}
+
+	if !recorded {
+		return Quote{}, fmt.Errorf("symbol %s not found in the market data replay", symbol)
+	}
+	if !found {
+		return Quote{}, fmt.Errorf("no market data of symbol %s yet at %s of the replay", symbol, now.Format(time.RFC3339))
+	}
+
+	return quoteFromRecord(latest)
+}
+
+// GetDepth lay a depth curve around the quote of the symbol at the replay clock
+func (c *replayProvider) GetDepth(ctx context.Context, symbol string) (Depth, error) {
+
+	quote, err := c.GetQuote(ctx, symbol)
+	if err != nil {
+		return Depth{}, err
+	}
+	return depthCurve(quote, c.depthLevels, c.depthStep), nil
+}
+
+// ExchangeInfo list the symbols of the recording, without exchange filters
+func (c *replayProvider) ExchangeInfo(ctx context.Context) ([]SymbolInfo, error) {
+
+	seen := make(map[string]bool)
+	var symbols []SymbolInfo
+	for key := range c.records {
+		symbol, _, _ := strings.Cut(key, "@")
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, syntheticSymbolInfo(symbol))
+		}
+	}
+	return symbols, nil
+}
+
+// Stream send the recorded messages of the symbol from the current replay clock,
+// each one when the replay clock reach its time
+func (c *replayProvider) Stream(ctx context.Context, symbol string, kind StreamKind) (Stream, error) {
+
+	records, ok := c.records[streamKey(symbol, kind)]
+	if !ok {
+		return nil, fmt.Errorf("stream %s@%s not found in the market data replay", symbol, kind)
+	}
+
+	now := c.replayTime(time.Now())
+	next := sort.Search(len(records), func(i int) bool {
+		return records[i].Time.After(now)
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &replayStream{
+		ctx:      ctx,
+		cancel:   cancel,
+		provider: c,
+		records:  records,
+		next:     next,
+	}, nil
+}
+
+// time on the recording for the wall clock time
+func (c *replayProvider) replayTime(now time.Time) time.Time {
+	elapsed := time.Duration(float64(now.Sub(c.startedAt)) * c.speed)
+	return c.firstTime.Add(elapsed)
+}
+
+// wall clock time the recording time is replayed at
+func (c *replayProvider) wallTime(recordTime time.Time) time.Time {
+	elapsed := time.Duration(float64(recordTime.Sub(c.firstTime)) / c.speed)
+	return c.startedAt.Add(elapsed)
+}
+
+type replayStream struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	provider *replayProvider
+	records  []Record
+	next     int
+}
+
+func (s *replayStream) Read() ([]byte, error) {
+
+	if s.next >= len(s.records) {
+		return nil, ErrReplayFinished
+	}
+	record := s.records[s.next]
+
+	timer := time.NewTimer(time.Until(s.provider.wallTime(record.Time)))
+	defer timer.Stop()
+
+	select {
+	case <-s.ctx.Done():
+		return nil, fmt.Errorf("replay stream closed: %w", s.ctx.Err())
+	case <-timer.C:
+		s.next++
+		return record.Data, nil
+	}
+}
+
+func (s *replayStream) Close() error {
+	s.cancel()
+	return nil
+}
+
+// read all the records of a JSON lines recording file
+func loadRecords(path string) ([]Record, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open market data replay file: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid record on line %d of %s: %w", line, path, err)
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read market data replay file: %w", err)
+	}
+	return records, nil
+}
+
+// take the best bid/ask out of a recorded ticker or bookTicker message
+func quoteFromRecord(record Record) (Quote, error) {
+
+	var data struct {
+		BidPrice string `json:"b"`
+		BidQty   string `json:"B"`
+		AskPrice string `json:"a"`
+		AskQty   string `json:"A"`
+	}
+	if err := json.Unmarshal(record.Data, &data); err != nil {
+		return Quote{}, fmt.Errorf("failed to decode recorded market data: %w", err)
+	}
+
+	quote := Quote{Symbol: record.Symbol}
+	for _, field := range []struct {
+		value  string
+		target *float64
+	}{
+		{data.BidPrice, &quote.BidPrice},
+		{data.BidQty, &quote.BidQty},
+		{data.AskPrice, &quote.AskPrice},
+		{data.AskQty, &quote.AskQty},
+	} {
+		value, err := strconv.ParseFloat(field.value, 64)
+		if err != nil {
+			return Quote{}, fmt.Errorf("recorded %s message of %s has no bid/ask", record.Stream, record.Symbol)
+		}
+		*field.target = value
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,70 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func newTestReplayProvider(records ...Record) *replayProvider {
+
+	provider := &replayProvider{
+		speed:     1,
+		startedAt: syntheticStart,
+		firstTime: syntheticStart,
+		records:   make(map[string][]Record),
+	}
+	for _, record := range records {
+		key := streamKey(record.Symbol, record.Stream)
+		provider.records[key] = append(provider.records[key], record)
+	}
+	return provider
+}
+
+func replayRecord(symbol string, kind StreamKind, second int, bid string) Record {
+	data, _ := json.Marshal(map[string]string{"b": bid, "B": "1", "a": bid, "A": "1"})
+	return Record{
+		Time:   syntheticStart.Add(time.Duration(second) * time.Second),
+		Symbol: symbol,
+		Stream: kind,
+		Data:   data,
+	}
+}
+
+func TestReplayQuoteAt(t *testing.T) {
+
+	provider := newTestReplayProvider(
+		replayRecord("BTCUSDT", StreamTicker, 0, "100"),
+		replayRecord("BTCUSDT", StreamTicker, 10, "101"),
+		replayRecord("ETHUSDT", StreamBookTicker, 5, "10"),
+		replayRecord("ETHUSDT", StreamTicker, 20, "11"),
+	)
+
+	tests := []struct {
+		name   string
+		symbol string
+		second int
+		bid    float64
+		err    bool
+	}{
+		{name: "first record", symbol: "BTCUSDT", second: 0, bid: 100},
+		{name: "between records", symbol: "BTCUSDT", second: 9, bid: 100},
+		{name: "after the last record", symbol: "BTCUSDT", second: 60, bid: 101},
+		{name: "before the first record of the symbol", symbol: "ETHUSDT", second: 2, err: true},
+		{name: "stream starting later skipped", symbol: "ETHUSDT", second: 8, bid: 10},
+		{name: "latest of the streams", symbol: "ETHUSDT", second: 25, bid: 11},
+		{name: "symbol not recorded", symbol: "DOGEUSDT", second: 30, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := provider.quoteAt(tt.symbol, syntheticStart.Add(time.Duration(tt.second)*time.Second))
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			if quote.BidPrice != tt.bid {
+				t.Errorf("bid %v, want %v", quote.BidPrice, tt.bid)
+			}
+		})
+	}
+}
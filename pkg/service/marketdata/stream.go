@@ -0,0 +1,81 @@
+package marketdata
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidStream = errors.New("invalid stream name")
+
+// kline intervals supported by Binance
+var klineIntervals = map[string]time.Duration{
+	"1s":  time.Second,
+	"1m":  time.Minute,
+	"3m":  3 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"2h":  2 * time.Hour,
+	"4h":  4 * time.Hour,
+	"6h":  6 * time.Hour,
+	"8h":  8 * time.Hour,
+	"12h": 12 * time.Hour,
+	"1d":  24 * time.Hour,
+	"3d":  3 * 24 * time.Hour,
+	"1w":  7 * 24 * time.Hour,
+	"1M":  30 * 24 * time.Hour,
+}
+
+// ParseStreamKind validate the stream kind, like "ticker" or "kline_5m"
+func ParseStreamKind(value string) (StreamKind, error) {
+
+	switch kind := StreamKind(value); kind {
+	case StreamTicker, StreamBookTicker, StreamTrade:
+		return kind, nil
+	}
+
+	if _, ok := KlineInterval(StreamKind(value)); ok {
+		return StreamKind(value), nil
+	}
+	return "", fmt.Errorf("%w: unsupported stream %q", ErrInvalidStream, value)
+}
+
+// ParseStreamName split a stream name like "btcusdt@ticker" into its symbol and kind
+func ParseStreamName(name string) (symbol string, kind StreamKind, err error) {
+
+	symbolPart, kindPart, found := strings.Cut(name, "@")
+	if !found || symbolPart == "" {
+		return "", "", fmt.Errorf("%w: %q, expected <symbol>@<stream>", ErrInvalidStream, name)
+	}
+
+	kind, err = ParseStreamKind(kindPart)
+	if err != nil {
+		return "", "", err
+	}
+	return NormalizeSymbol(symbolPart), kind, nil
+}
+
+// StreamName of a symbol and kind as clients see it, like "btcusdt@ticker"
+func StreamName(symbol string, kind StreamKind) string {
+	return fmt.Sprintf("%s@%s", strings.ToLower(symbol), kind)
+}
+
+// KlineInterval return the candle duration of a kline stream
+func KlineInterval(kind StreamKind) (time.Duration, bool) {
+
+	interval, found := strings.CutPrefix(string(kind), streamKlinePrefix)
+	if !found {
+		return 0, false
+	}
+
+	duration, ok := klineIntervals[interval]
+	return duration, ok
+}
+
+// key of the stream of a symbol, in the format "BTCUSDT@ticker"
+func streamKey(symbol string, kind StreamKind) string {
+	return fmt.Sprintf("%s@%s", symbol, kind)
+}
@@ -0,0 +1,454 @@
+package marketdata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+)
+
+// seconds in a year, used to scale the annualized drift and volatility to one tick
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// syntheticProvider generate prices offline using a seeded geometric Brownian motion.
+// The path of a symbol start at the start price on the tick it's first read and move one tick
+// per tick interval from there. The quotes, the depth and the streams all read it at the tick
+// of their time, so the same seed always produce the same price at the same tick of the path
+// however many callers read it.
+type syntheticProvider struct {
+	seed         int64
+	drift        float64
+	volatility   float64
+	spread       float64
+	tickInterval time.Duration
+	startPrices  map[string]float64
+	depthLevels  int
+	depthStep    float64
+	startedAt    time.Time
+
+	mu    sync.Mutex
+	paths map[string]*syntheticPath
+}
+
+// NewSyntheticProvider create an offline provider from the synthetic settings of the config
+func NewSyntheticProvider(cfg config.Config) (MarketDataProvider, error) {
+
+	startPrices, err := parseStartPrices(cfg.SyntheticStartPrices)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syntheticProvider{
+		seed:         cfg.SyntheticSeed,
+		drift:        cfg.SyntheticDrift,
+		volatility:   cfg.SyntheticVolatility,
+		spread:       cfg.SyntheticSpread,
+		tickInterval: cfg.SyntheticTickInterval,
+		startPrices:  startPrices,
+		depthLevels:  cfg.MarketDepthLevels,
+		depthStep:    cfg.MarketDepthStep,
+		startedAt:    time.Now(),
+		paths:        make(map[string]*syntheticPath),
+	}, nil
+}
+
+// GetQuote return the bid/ask of the path of the symbol at the current tick
+func (c *syntheticProvider) GetQuote(ctx context.Context, symbol string) (Quote, error) {
+
+	path, err := c.pathAt(symbol, time.Now())
+	if err != nil {
+		return Quote{}, err
+	}
+	return path.quote(symbol), nil
+}
+
+// GetDepth lay a depth curve around the quote of the symbol at the current tick
+func (c *syntheticProvider) GetDepth(ctx context.Context, symbol string) (Depth, error) {
+
+	quote, err := c.GetQuote(ctx, symbol)
+	if err != nil {
+		return Depth{}, err
+	}
+	return depthCurve(quote, c.depthLevels, c.depthStep), nil
+}
+
+// ExchangeInfo list the symbols with a start price, without exchange filters
+func (c *syntheticProvider) ExchangeInfo(ctx context.Context) ([]SymbolInfo, error) {
+
+	symbols := make([]SymbolInfo, 0, len(c.startPrices))
+	for symbol := range c.startPrices {
+		symbols = append(symbols, syntheticSymbolInfo(symbol))
+	}
+	return symbols, nil
+}
+
+// symbol of a feed without exchange metadata, quoted in the default quote asset
+func syntheticSymbolInfo(symbol string) SymbolInfo {
+	return SymbolInfo{
+		Symbol:     symbol,
+		Status:     SymbolStatusTrading,
+		BaseAsset:  strings.TrimSuffix(symbol, DefaultQuoteAsset),
+		QuoteAsset: DefaultQuoteAsset,
+	}
+}
+
+// Stream send the price path of the symbol at the tick of every interval, the same path the quotes read
+func (c *syntheticProvider) Stream(ctx context.Context, symbol string, kind StreamKind) (Stream, error) {
+
+	if _, err := ParseStreamKind(string(kind)); err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.startPrices[symbol]; !ok {
+		return nil, fmt.Errorf("symbol %s not supported by the synthetic feed", symbol)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &syntheticStream{
+		ctx:      ctx,
+		cancel:   cancel,
+		symbol:   symbol,
+		kind:     kind,
+		provider: c,
+		ticker:   time.NewTicker(c.tickInterval),
+		tick:     c.tickInterval,
+	}, nil
+}
+
+// tick of the paths at t, counted from the start of the provider
+func (c *syntheticProvider) tickAt(t time.Time) int64 {
+	if t.Before(c.startedAt) {
+		return 0
+	}
+	return int64(t.Sub(c.startedAt) / c.tickInterval)
+}
+
+// state of the path of the symbol at the tick of t. the path only move forward, a time before
+// the tick it reached read that tick. a new path start at the tick of t rather than walking
+// every tick since the provider started
+func (c *syntheticProvider) pathAt(symbol string, t time.Time) (syntheticPath, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path, ok := c.paths[symbol]
+	if !ok {
+		var err error
+		if path, err = c.newPath(symbol, c.tickAt(t)); err != nil {
+			return syntheticPath{}, err
+		}
+		c.paths[symbol] = path
+	}
+
+	for tick := c.tickAt(t); path.ticks < tick; {
+		path.next()
+	}
+	return *path, nil
+}
+
+// create the price path of the symbol at the start tick, seeded from the provider seed and the symbol name
+func (c *syntheticProvider) newPath(symbol string, start int64) (*syntheticPath, error) {
+
+	startPrice, ok := c.startPrices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("symbol %s not supported by the synthetic feed", symbol)
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(symbol))
+
+	dt := c.tickInterval.Seconds() / secondsPerYear
+
+	return &syntheticPath{
+		rng:       rand.New(rand.NewSource(c.seed ^ int64(hash.Sum64()))),
+		spread:    c.spread,
+		drift:     (c.drift - c.volatility*c.volatility/2) * dt,
+		diffusion: c.volatility * math.Sqrt(dt),
+		ticks:     start,
+		price:     startPrice,
+		open:      startPrice,
+		high:      startPrice,
+		low:       startPrice,
+	}, nil
+}
+
+// parse start prices in the format "BTCUSDT=60000,ETHUSDT=3000"
+func parseStartPrices(value string) (map[string]float64, error) {
+
+	startPrices := make(map[string]float64)
+
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		symbol, price, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid synthetic start price %q, expected SYMBOL=PRICE", pair)
+		}
+
+		startPrice, err := strconv.ParseFloat(strings.TrimSpace(price), 64)
+		if err != nil || startPrice <= 0 {
+			return nil, fmt.Errorf("invalid synthetic start price for %s: %q", symbol, price)
+		}
+
+		startPrices[NormalizeSymbol(symbol)] = startPrice
+	}
+
+	return startPrices, nil
+}
+
+// syntheticPath is the random walk of one symbol
+type syntheticPath struct {
+	rng       *rand.Rand
+	spread    float64
+	drift     float64
+	diffusion float64
+
+	ticks  int64
+	price  float64
+	qty    float64
+	open   float64
+	high   float64
+	low    float64
+	volume float64
+	// side of the trade at this tick
+	buyerIsMaker bool
+}
+
+// move the price one tick forward
+func (p *syntheticPath) next() *syntheticPath {
+
+	p.ticks++
+	p.price *= math.Exp(p.drift + p.diffusion*p.rng.NormFloat64())
+	// trade between 50k and 500k of the quote asset at this tick
+	p.qty = (50_000 + p.rng.Float64()*450_000) / p.price
+	p.buyerIsMaker = p.rng.Intn(2) == 0
+
+	p.high = math.Max(p.high, p.price)
+	p.low = math.Min(p.low, p.price)
+	p.volume += p.qty
+
+	return p
+}
+
+func (p *syntheticPath) quote(symbol string) Quote {
+	return Quote{
+		Symbol:   symbol,
+		BidPrice: p.price * (1 - p.spread/2),
+		AskPrice: p.price * (1 + p.spread/2),
+		BidQty:   p.qty,
+		AskQty:   p.qty,
+	}
+}
+
+// binance style 24hr ticker message of the path
+func (p *syntheticPath) ticker(symbol string, eventTime time.Time) syntheticTicker {
+	quote := p.quote(symbol)
+
+	return syntheticTicker{
+		EventType:          "24hrTicker",
+		EventTime:          eventTime.UnixMilli(),
+		Symbol:             symbol,
+		PriceChange:        formatFloat(p.price - p.open),
+		PriceChangePercent: formatFloat((p.price - p.open) / p.open * 100),
+		LastPrice:          formatFloat(p.price),
+		LastQty:            formatFloat(p.qty),
+		BidPrice:           formatFloat(quote.BidPrice),
+		BidQty:             formatFloat(quote.BidQty),
+		AskPrice:           formatFloat(quote.AskPrice),
+		AskQty:             formatFloat(quote.AskQty),
+		OpenPrice:          formatFloat(p.open),
+		HighPrice:          formatFloat(p.high),
+		LowPrice:           formatFloat(p.low),
+		Volume:             formatFloat(p.volume),
+	}
+}
+
+type syntheticTicker struct {
+	EventType          string `json:"e"`
+	EventTime          int64  `json:"E"`
+	Symbol             string `json:"s"`
+	PriceChange        string `json:"p"`
+	PriceChangePercent string `json:"P"`
+	LastPrice          string `json:"c"`
+	LastQty            string `json:"Q"`
+	BidPrice           string `json:"b"`
+	BidQty             string `json:"B"`
+	AskPrice           string `json:"a"`
+	AskQty             string `json:"A"`
+	OpenPrice          string `json:"o"`
+	HighPrice          string `json:"h"`
+	LowPrice           string `json:"l"`
+	Volume             string `json:"v"`
+}
+
+// binance style bookTicker message of the path
+func (p *syntheticPath) bookTicker(symbol string) syntheticBookTicker {
+	quote := p.quote(symbol)
+
+	return syntheticBookTicker{
+		UpdateID: p.ticks,
+		Symbol:   symbol,
+		BidPrice: formatFloat(quote.BidPrice),
+		BidQty:   formatFloat(quote.BidQty),
+		AskPrice: formatFloat(quote.AskPrice),
+		AskQty:   formatFloat(quote.AskQty),
+	}
+}
+
+// binance style trade message of the path, the side of the trade is random
+func (p *syntheticPath) trade(symbol string, eventTime time.Time) syntheticTrade {
+	return syntheticTrade{
+		EventType:    "trade",
+		EventTime:    eventTime.UnixMilli(),
+		Symbol:       symbol,
+		TradeID:      p.ticks,
+		Price:        formatFloat(p.price),
+		Qty:          formatFloat(p.qty),
+		TradeTime:    eventTime.UnixMilli(),
+		BuyerIsMaker: p.buyerIsMaker,
+	}
+}
+
+type syntheticBookTicker struct {
+	UpdateID int64  `json:"u"`
+	Symbol   string `json:"s"`
+	BidPrice string `json:"b"`
+	BidQty   string `json:"B"`
+	AskPrice string `json:"a"`
+	AskQty   string `json:"A"`
+}
+
+type syntheticTrade struct {
+	EventType    string `json:"e"`
+	EventTime    int64  `json:"E"`
+	Symbol       string `json:"s"`
+	TradeID      int64  `json:"t"`
+	Price        string `json:"p"`
+	Qty          string `json:"q"`
+	TradeTime    int64  `json:"T"`
+	BuyerIsMaker bool   `json:"m"`
+}
+
+type syntheticKline struct {
+	EventType string               `json:"e"`
+	EventTime int64                `json:"E"`
+	Symbol    string               `json:"s"`
+	Kline     syntheticKlineCandle `json:"k"`
+}
+
+type syntheticKlineCandle struct {
+	StartTime int64  `json:"t"`
+	CloseTime int64  `json:"T"`
+	Symbol    string `json:"s"`
+	Interval  string `json:"i"`
+	Open      string `json:"o"`
+	Close     string `json:"c"`
+	High      string `json:"h"`
+	Low       string `json:"l"`
+	Volume    string `json:"v"`
+	Trades    int64  `json:"n"`
+	IsClosed  bool   `json:"x"`
+}
+
+type syntheticStream struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	symbol   string
+	kind     StreamKind
+	provider *syntheticProvider
+	ticker   *time.Ticker
+	tick     time.Duration
+
+	// current candle of a kline stream
+	candleStart  time.Time
+	candleOpen   float64
+	candleHigh   float64
+	candleLow    float64
+	candleVolume float64
+	candleTrades int64
+}
+
+func (s *syntheticStream) Read() ([]byte, error) {
+	select {
+	case <-s.ctx.Done():
+		return nil, fmt.Errorf("synthetic stream closed: %w", s.ctx.Err())
+	case eventTime := <-s.ticker.C:
+		message, err := s.message(eventTime)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(message)
+	}
+}
+
+// read the path at the tick of the event and build the message of the stream kind
+func (s *syntheticStream) message(eventTime time.Time) (interface{}, error) {
+
+	path, err := s.provider.pathAt(s.symbol, eventTime)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s.kind {
+	case StreamBookTicker:
+		return path.bookTicker(s.symbol), nil
+	case StreamTrade:
+		return path.trade(s.symbol, eventTime), nil
+	case StreamTicker:
+		return path.ticker(s.symbol, eventTime), nil
+	}
+
+	interval, _ := KlineInterval(s.kind)
+	start := eventTime.Truncate(interval)
+
+	// start a new candle once the interval is over
+	if !s.candleStart.Equal(start) {
+		s.candleStart = start
+		s.candleOpen, s.candleHigh, s.candleLow = path.price, path.price, path.price
+		s.candleVolume, s.candleTrades = 0, 0
+	}
+
+	s.candleHigh = math.Max(s.candleHigh, path.price)
+	s.candleLow = math.Min(s.candleLow, path.price)
+	s.candleVolume += path.qty
+	s.candleTrades++
+
+	return syntheticKline{
+		EventType: "kline",
+		EventTime: eventTime.UnixMilli(),
+		Symbol:    s.symbol,
+		Kline: syntheticKlineCandle{
+			StartTime: start.UnixMilli(),
+			CloseTime: start.Add(interval).UnixMilli() - 1,
+			Symbol:    s.symbol,
+			Interval:  strings.TrimPrefix(string(s.kind), streamKlinePrefix),
+			Open:      formatFloat(s.candleOpen),
+			Close:     formatFloat(path.price),
+			High:      formatFloat(s.candleHigh),
+			Low:       formatFloat(s.candleLow),
+			Volume:    formatFloat(s.candleVolume),
+			Trades:    s.candleTrades,
+			// the next tick fall in the next candle
+			IsClosed: !eventTime.Add(s.tick).Before(start.Add(interval)),
+		},
+	}, nil
+}
+
+func (s *syntheticStream) Close() error {
+	s.ticker.Stop()
+	s.cancel()
+	return nil
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 8, 64)
+}
@@ -0,0 +1,165 @@
+package marketdata
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+)
+
+var syntheticStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestSyntheticProvider(t *testing.T, seed int64) *syntheticProvider {
+	t.Helper()
+
+	provider, err := NewSyntheticProvider(config.Config{
+		SyntheticSeed:         seed,
+		SyntheticDrift:        0.05,
+		SyntheticVolatility:   0.8,
+		SyntheticSpread:       0.001,
+		SyntheticStartPrices:  "BTCUSDT=60000",
+		SyntheticTickInterval: time.Second,
+		MarketDepthLevels:     5,
+		MarketDepthStep:       0.0005,
+	})
+	if err != nil {
+		t.Fatalf("failed to create synthetic provider: %v", err)
+	}
+
+	synthetic := provider.(*syntheticProvider)
+	synthetic.startedAt = syntheticStart
+	return synthetic
+}
+
+func syntheticTick(tick int) time.Time {
+	return syntheticStart.Add(time.Duration(tick)*time.Second + 300*time.Millisecond)
+}
+
+func TestSyntheticPathSeeded(t *testing.T) {
+
+	// mid prices of the BTCUSDT path of seed 42 at ticks 0 to 5
+	want := []float64{60000, 59995.91515571, 59988.74233703, 59999.50408363, 59994.89092171, 60011.09359610}
+
+	provider := newTestSyntheticProvider(t, 42)
+	for tick, price := range want {
+		path, err := provider.pathAt("BTCUSDT", syntheticTick(tick))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(path.price-price) > 1e-6 {
+			t.Errorf("tick %d: price %.8f, want %.8f", tick, path.price, price)
+		}
+		if path.ticks != int64(tick) {
+			t.Errorf("tick %d: path at tick %d", tick, path.ticks)
+		}
+	}
+}
+
+func TestSyntheticPathIndependentOfCallers(t *testing.T) {
+
+	busy := newTestSyntheticProvider(t, 7)
+	idle := newTestSyntheticProvider(t, 7)
+
+	// one provider is read many times on every tick, the other only at the first and the last one
+	var want syntheticPath
+	for tick := 0; tick <= 10; tick++ {
+		for i := 0; i < 3; i++ {
+			path, err := busy.pathAt("BTCUSDT", syntheticTick(tick))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want = path
+		}
+	}
+
+	if _, err := idle.pathAt("BTCUSDT", syntheticTick(0)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := idle.pathAt("BTCUSDT", syntheticTick(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.price != want.price || got.qty != want.qty || got.volume != want.volume {
+		t.Errorf("path read twice is %+v, read on every tick %+v", got.quote("BTCUSDT"), want.quote("BTCUSDT"))
+	}
+
+	// a time before the tick the path reached read that tick
+	past, err := busy.pathAt("BTCUSDT", syntheticTick(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if past.ticks != 10 {
+		t.Errorf("path went back to tick %d", past.ticks)
+	}
+
+	other := newTestSyntheticProvider(t, 8)
+	if _, err := other.pathAt("BTCUSDT", syntheticTick(0)); err != nil {
+		t.Fatal(err)
+	}
+	path, err := other.pathAt("BTCUSDT", syntheticTick(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path.price == want.price {
+		t.Errorf("seeds 7 and 8 produced the same price %v", path.price)
+	}
+}
+
+func TestSyntheticPathStartsOnFirstRead(t *testing.T) {
+
+	// a path first read long after the provider started begin there at the start price, with
+	// the same walk as a path read from the start of the provider
+	provider := newTestSyntheticProvider(t, 42)
+	late := syntheticTick(30 * 24 * 60 * 60)
+
+	path, err := provider.pathAt("BTCUSDT", late)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path.price != 60000 || path.ticks != provider.tickAt(late) {
+		t.Errorf("path started at tick %d with price %v", path.ticks, path.price)
+	}
+
+	path, err = provider.pathAt("BTCUSDT", late.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(path.price-59995.91515571) > 1e-6 {
+		t.Errorf("price %.8f one tick after the start, want %.8f", path.price, 59995.91515571)
+	}
+}
+
+func TestSyntheticStreamReadsQuotePath(t *testing.T) {
+
+	provider := newTestSyntheticProvider(t, 42)
+
+	stream, err := provider.Stream(context.Background(), "BTCUSDT", StreamBookTicker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	for tick := 1; tick <= 3; tick++ {
+		message, err := stream.(*syntheticStream).message(syntheticTick(tick))
+		if err != nil {
+			t.Fatal(err)
+		}
+		path, err := provider.pathAt("BTCUSDT", syntheticTick(tick))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bookTicker := message.(syntheticBookTicker)
+		quote := path.quote("BTCUSDT")
+		if bookTicker.UpdateID != int64(tick) || bookTicker.BidPrice != formatFloat(quote.BidPrice) ||
+			bookTicker.AskPrice != formatFloat(quote.AskPrice) {
+			t.Errorf("tick %d: stream sent %+v, quote is %+v", tick, bookTicker, quote)
+		}
+	}
+
+	if _, err := provider.Stream(context.Background(), "DOGEUSDT", StreamBookTicker); err == nil {
+		t.Error("stream of a symbol without a start price was opened")
+	}
+}
@@ -0,0 +1,10 @@
+package marketdata
+
+// Quote is the best bid/ask of a symbol, decoded from the Binance bookTicker
+type Quote struct {
+	Symbol   string  `json:"symbol"`
+	BidPrice float64 `json:"bidPrice,string"`
+	AskPrice float64 `json:"askPrice,string"`
+	BidQty   float64 `json:"bidQty,string"`
+	AskQty   float64 `json:"askQty,string"`
+}
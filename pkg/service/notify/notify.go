@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+)
+
+// events pushed to the users
+const (
+	// the daily loss of the user tripped their kill switch
+	EventStopOut = "stop_out"
+	// an admin reset the kill switch of the user
+	EventStopOutReset = "stop_out_reset"
+)
+
+// Event is a message for one user, delivered to every account socket they have open
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+	Time time.Time   `json:"time"`
+}
+
+// Notifier push events to the users connected to it, an event for a user without a socket is dropped
+type Notifier interface {
+	// add a socket of the user, the events come on the channel until the cancel func is called
+	Subscribe(uid uint) (<-chan Event, func())
+	Publish(uid uint, event Event)
+}
+
+type notifier struct {
+	buffer int
+
+	mu          sync.Mutex
+	subscribers map[uint]map[chan Event]struct{}
+}
+
+func NewNotifier(cfg config.Config) Notifier {
+	return &notifier{
+		buffer:      cfg.MarketHubClientBuffer,
+		subscribers: make(map[uint]map[chan Event]struct{}),
+	}
+}
+
+func (n *notifier) Subscribe(uid uint) (<-chan Event, func()) {
+
+	events := make(chan Event, n.buffer)
+
+	n.mu.Lock()
+	if n.subscribers[uid] == nil {
+		n.subscribers[uid] = make(map[chan Event]struct{})
+	}
+	n.subscribers[uid][events] = struct{}{}
+	n.mu.Unlock()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			delete(n.subscribers[uid], events)
+			if len(n.subscribers[uid]) == 0 {
+				delete(n.subscribers, uid)
+			}
+			close(events)
+		})
+	}
+}
+
+func (n *notifier) Publish(uid uint, event Event) {
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for events := range n.subscribers[uid] {
+		// a socket too slow to take the event miss it rather than block the publisher
+		select {
+		case events <- event:
+		default:
+			log.Printf("Dropped %s event of user %d, socket buffer full", event.Type, uid)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// executionSim is the simulated exchange between the user and the order book, the latency
+// before an order reach the book, the orders it reject and the fills of the limit orders only touched.
+// every draw come from one seeded source so a run with the same seed and orders draw the same.
+type executionSim struct {
+	latencyMin       time.Duration
+	latencyMax       time.Duration
+	rejectRate       float64
+	touchPartialRate float64
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newExecutionSim(cfg config.Config) *executionSim {
+	return &executionSim{
+		latencyMin:       cfg.ExecutionLatencyMin,
+		latencyMax:       cfg.ExecutionLatencyMax,
+		rejectRate:       cfg.ExecutionRejectRate,
+		touchPartialRate: cfg.ExecutionTouchPartialRate,
+		rng:              rand.New(rand.NewSource(cfg.ExecutionSeed)),
+	}
+}
+
+// latency of the next order and whether the exchange reject it
+func (s *executionSim) accept() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latency := s.latencyMin
+	if spread := s.latencyMax - s.latencyMin; spread > 0 {
+		latency += time.Duration(s.rng.Int63n(int64(spread) + 1))
+	}
+	return latency, s.rng.Float64() < s.rejectRate
+}
+
+// quantity of qty a limit order touched but not traded through fill, the orders ahead of it
+// at its price may take all but a random share of the level. it's drawn on every matching pass,
+// so an order resting at the touch keep filling a share of its rest each pass until it's filled
+func (s *executionSim) touchFill(qty float64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rng.Float64() >= s.touchPartialRate {
+		return qty
+	}
+	return qty * (1 - s.rng.Float64())
+}
+
+// wait until the new order reach the exchange, it's priced by the book of then.
+// an order the exchange reject is refused.
+func (c *orderUseCase) submitOrder(ctx context.Context) error {
+
+	latency, rejected := c.execution.accept()
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("order submission cancelled: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	if rejected {
+		return status.Errorf(codes.FailedPrecondition, "order rejected by the exchange")
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExecutionSimAccept(t *testing.T) {
+
+	type draw struct {
+		latency  time.Duration
+		rejected bool
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want []draw
+	}{
+		{
+			name: "no latency and no rejection",
+			cfg:  config.Config{ExecutionSeed: 42},
+			want: []draw{{0, false}, {0, false}, {0, false}},
+		},
+		{
+			name: "fixed latency",
+			cfg: config.Config{ExecutionSeed: 42, ExecutionLatencyMin: 20 * time.Millisecond,
+				ExecutionLatencyMax: 20 * time.Millisecond},
+			want: []draw{{20 * time.Millisecond, false}, {20 * time.Millisecond, false}, {20 * time.Millisecond, false}},
+		},
+		{
+			name: "reject everything",
+			cfg:  config.Config{ExecutionSeed: 42, ExecutionRejectRate: 1},
+			want: []draw{{0, true}, {0, true}, {0, true}},
+		},
+		{
+			name: "seeded latency and rejections",
+			cfg: config.Config{ExecutionSeed: 42, ExecutionLatencyMin: 10 * time.Millisecond,
+				ExecutionLatencyMax: 50 * time.Millisecond, ExecutionRejectRate: 0.3},
+			want: []draw{
+				{26796970 * time.Nanosecond, true},
+				{17323790 * time.Nanosecond, true},
+				{49699267 * time.Nanosecond, false},
+				{16811811 * time.Nanosecond, false},
+				{11411531 * time.Nanosecond, false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := newExecutionSim(tt.cfg)
+			for i, want := range tt.want {
+				latency, rejected := sim.accept()
+				if latency != want.latency || rejected != want.rejected {
+					t.Errorf("draw %d: got (%v, %v), want (%v, %v)", i, latency, rejected, want.latency, want.rejected)
+				}
+			}
+		})
+	}
+}
+
+func TestExecutionSimTouchFill(t *testing.T) {
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		qty  float64
+		want []float64
+	}{
+		{
+			name: "touch fill everything",
+			cfg:  config.Config{ExecutionSeed: 42},
+			qty:  2,
+			want: []float64{2, 2, 2},
+		},
+		{
+			name: "touch fill part every time",
+			cfg:  config.Config{ExecutionSeed: 42, ExecutionTouchPartialRate: 1},
+			qty:  2,
+			want: []float64{1.8679990064129641, 1.5823625938906818, 1.2336134001552286},
+		},
+		{
+			name: "seeded touch fills",
+			cfg:  config.Config{ExecutionSeed: 7, ExecutionTouchPartialRate: 0.5},
+			qty:  1.5,
+			want: []float64{1.5, 1.1379186494020535, 1.5, 1.5, 0.9686779528834388},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := newExecutionSim(tt.cfg)
+			for i, want := range tt.want {
+				if got := sim.touchFill(tt.qty); math.Abs(got-want) > 1e-9 {
+					t.Errorf("fill %d: got %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmitOrder(t *testing.T) {
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		ctx  context.Context
+		code codes.Code
+		err  bool
+	}{
+		{
+			name: "accepted",
+			cfg:  config.Config{ExecutionSeed: 42, ExecutionLatencyMin: time.Millisecond, ExecutionLatencyMax: time.Millisecond},
+			ctx:  context.Background(),
+		},
+		{
+			name: "rejected",
+			cfg:  config.Config{ExecutionSeed: 42, ExecutionRejectRate: 1},
+			ctx:  context.Background(),
+			code: codes.FailedPrecondition,
+			err:  true,
+		},
+		{
+			name: "cancelled before reaching the exchange",
+			cfg:  config.Config{ExecutionSeed: 42, ExecutionLatencyMin: time.Minute, ExecutionLatencyMax: time.Minute},
+			ctx:  cancelled,
+			code: codes.Unknown,
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &orderUseCase{execution: newExecutionSim(tt.cfg)}
+
+			err := c.submitOrder(tt.ctx)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			if err != nil && status.Code(err) != tt.code {
+				t.Errorf("got code %v, want %v", status.Code(err), tt.code)
+			}
+		})
+	}
+}
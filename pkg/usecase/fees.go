@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// trading the fee tier of a user is picked by
+const feeTierWindow = 30 * 24 * time.Hour
+
+// feeTier is the maker and taker rates of the users who traded at least MinValue in the tier window
+type feeTier struct {
+	MinValue float64
+	Maker    float64
+	Taker    float64
+}
+
+// feeSchedule is the base maker and taker rates and the tiers lowering them, by MinValue
+type feeSchedule struct {
+	base  feeTier
+	tiers []feeTier
+}
+
+func newFeeSchedule(cfg config.Config) (feeSchedule, error) {
+
+	tiers, err := parseFeeTiers(cfg.FeeTiers)
+	if err != nil {
+		return feeSchedule{}, err
+	}
+
+	return feeSchedule{
+		base:  feeTier{Maker: cfg.FeeMakerRate, Taker: cfg.FeeTakerRate},
+		tiers: tiers,
+	}, nil
+}
+
+// parse the tiers of a comma separated list of MIN_VALUE:MAKER:TAKER
+func parseFeeTiers(value string) ([]feeTier, error) {
+
+	var tiers []feeTier
+	for _, tier := range strings.Split(value, ",") {
+		if strings.TrimSpace(tier) == "" {
+			continue
+		}
+
+		fields := strings.Split(tier, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid fee tier %q, expected MIN_VALUE:MAKER:TAKER", tier)
+		}
+
+		var numbers [3]float64
+		for i, field := range fields {
+			number, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil || number < 0 || i > 0 && number >= 1 {
+				return nil, fmt.Errorf("invalid fee tier %q", tier)
+			}
+			numbers[i] = number
+		}
+
+		tiers = append(tiers, feeTier{MinValue: numbers[0], Maker: numbers[1], Taker: numbers[2]})
+	}
+
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinValue < tiers[j].MinValue })
+	return tiers, nil
+}
+
+// rate of a fill of the liquidity for a user who traded value in the tier window
+func (s feeSchedule) rate(value float64, liquidity string) float64 {
+
+	tier := s.base
+	for _, t := range s.tiers {
+		if value >= t.MinValue {
+			tier = t
+		}
+	}
+
+	if liquidity == domain.LiquidityMaker {
+		return tier.Maker
+	}
+	return tier.Taker
+}
+
+// highest rate a fill can be charged, a buy reserve it on top of its value
+func (s feeSchedule) maxRate() float64 {
+
+	rate := math.Max(s.base.Maker, s.base.Taker)
+	for _, t := range s.tiers {
+		rate = math.Max(rate, math.Max(t.Maker, t.Taker))
+	}
+	return rate
+}
+
+// fee rate of the next fill of the user, by the value they traded in the tier window
+func (c *orderUseCase) feeRate(ctx context.Context, uid uint, liquidity string) (float64, error) {
+
+	value, err := c.tradeRepo.GetTradedValue(ctx, uid, time.Now().Add(-feeTierWindow))
+	if err != nil {
+		return 0, err
+	}
+	return c.fees.rate(value, liquidity), nil
+}
+
+// pay the fee of the trade out of the free balance of its quote asset, it's added to the fees of the order
+func (c *orderUseCase) chargeFee(ctx context.Context, order *domain.Order, trade domain.Trade) error {
+
+	if trade.Fee <= 0 {
+		return nil
+	}
+
+	entries := newJournal(domain.LedgerKindFee, &order.ID, &trade.ID).
+		transfer(order.UserID, trade.FeeAsset, domain.LedgerAvailable, domain.LedgerFees, trade.Fee).entries
+
+	charged, err := c.accountRepo.Post(ctx, entries)
+	if err != nil {
+		return err
+	}
+	if !charged {
+		return status.Errorf(codes.FailedPrecondition, "insufficient funds: %v %s required for the fee of order %d",
+			trade.Fee, trade.FeeAsset, order.ID)
+	}
+
+	order.Fee += trade.Fee
+	return nil
+}
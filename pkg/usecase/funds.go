@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// relative rounding tolerated between the funds reserved for a fill and its cost
+const settleTolerance = 1e-9
+
+// price the funds of a buy are reserved at, its limit price or the price it's expected to fill at.
+// a market order is expected to fill at the volume weighted price of the levels it walk.
+func reservePrice(order domain.Order, book *bookLiquidity) (float64, error) {
+
+	switch order.Kind {
+	case domain.OrderKindLimit, domain.OrderKindStopLimit:
+		return order.LimitPrice, nil
+	case domain.OrderKindMarket:
+		if _, price, ok := book.peek(&order, order.Volume-order.FilledVolume); ok {
+			return price, nil
+		}
+		return marketPrice(book.quote, order.Type)
+	default:
+		return order.TriggerPrice, nil
+	}
+}
+
+// funds the rest of the order need at price, quote asset for a buy and base asset for a sell.
+// a buy also reserve the highest fee it can be charged, a sell pay its fee out of what it get.
+func (c *orderUseCase) reservation(order domain.Order, price float64) float64 {
+
+	remaining := order.Volume - order.FilledVolume
+	if order.Type == domain.OrderSideSell {
+		return remaining
+	}
+	return remaining * price * (1 + c.fees.maxRate())
+}
+
+// base and quote asset of the symbol, from the symbol registry
+func (c *orderUseCase) symbolAssets(ctx context.Context, symbol string) (base, quote string, err error) {
+
+	info, err := c.symbolRepo.GetSymbol(ctx, symbol)
+	if err != nil {
+		return "", "", err
+	}
+	if info.Symbol == "" {
+		return "", "", fmt.Errorf("symbol %s not found in the symbol registry", symbol)
+	}
+	return info.BaseAsset, info.QuoteAsset, nil
+}
+
+// asset the order reserve, the quote asset it pay with for a buy and the base asset it sell
+func (c *orderUseCase) reservedAsset(ctx context.Context, order domain.Order) (string, error) {
+
+	base, quote, err := c.symbolAssets(ctx, order.Symbol)
+	if err != nil {
+		return "", err
+	}
+	if order.Type == domain.OrderSideSell {
+		return base, nil
+	}
+	return quote, nil
+}
+
+// lock amount of the free balance for the order, an insufficient funds error when the balance is short
+func (c *orderUseCase) reserveFunds(ctx context.Context, order *domain.Order, amount float64) error {
+
+	if amount <= 0 {
+		return nil
+	}
+
+	asset, err := c.reservedAsset(ctx, *order)
+	if err != nil {
+		return err
+	}
+
+	entries := newJournal(domain.LedgerKindReserve, &order.ID, nil).
+		transfer(order.UserID, asset, domain.LedgerAvailable, domain.LedgerLocked, amount).entries
+
+	reserved, err := c.accountRepo.Post(ctx, entries)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		account, err := c.accountRepo.GetAccount(ctx, order.UserID, asset)
+		if err != nil {
+			return err
+		}
+		return status.Errorf(codes.FailedPrecondition, "insufficient funds: %v %s available, %v %s required",
+			account.Balance, asset, amount, asset)
+	}
+
+	order.Reserved += amount
+	return nil
+}
+
+// save the new order with amount reserved for it, the reservation is posted once the order has its ID
+func (c *orderUseCase) createReservedOrder(ctx context.Context, order *domain.Order, amount float64) error {
+
+	order.Reserved = amount
+	if err := c.createOrder(ctx, order); err != nil {
+		return err
+	}
+
+	order.Reserved = 0
+	return c.reserveFunds(ctx, order, amount)
+}
+
+// unlock the funds still reserved by the order
+func (c *orderUseCase) releaseFunds(ctx context.Context, order *domain.Order) error {
+
+	if order.Reserved <= 0 {
+		return nil
+	}
+
+	asset, err := c.reservedAsset(ctx, *order)
+	if err != nil {
+		return err
+	}
+
+	entries := newJournal(domain.LedgerKindRelease, &order.ID, nil).
+		transfer(order.UserID, asset, domain.LedgerLocked, domain.LedgerAvailable, order.Reserved).entries
+
+	if _, err := c.accountRepo.Post(ctx, entries); err != nil {
+		return err
+	}
+
+	order.Reserved = 0
+	return nil
+}
+
+// pay the exchange for the trade out of the funds reserved by the order and take what it bought,
+// the share of the reservation used by the trade is taken off the order
+func (c *orderUseCase) settleFill(ctx context.Context, order *domain.Order, trade domain.Trade) error {
+
+	qty, price := trade.Volume, trade.Price
+
+	base, quote, err := c.symbolAssets(ctx, order.Symbol)
+	if err != nil {
+		return err
+	}
+
+	// the reservation cover the rest of the order, the fill use its share of it
+	unlock := order.Reserved
+	if remaining := order.Volume - order.FilledVolume; qty < remaining {
+		unlock = order.Reserved * qty / remaining
+	}
+
+	paid, paidAmount, bought, boughtAmount := quote, qty*price, base, qty
+	if order.Type == domain.OrderSideSell {
+		paid, paidAmount, bought, boughtAmount = base, qty, quote, qty*price
+	}
+	if paidAmount > unlock && paidAmount-unlock <= paidAmount*settleTolerance {
+		paidAmount = unlock
+	}
+
+	// the unlocked share pay first, the free balance cover what it's short of
+	entries := newJournal(domain.LedgerKindFill, &order.ID, &trade.ID).
+		add(order.UserID, paid, domain.LedgerLocked, -unlock).
+		add(order.UserID, paid, domain.LedgerAvailable, unlock-paidAmount).
+		add(order.UserID, paid, domain.LedgerExchange, paidAmount).
+		transfer(order.UserID, bought, domain.LedgerExchange, domain.LedgerAvailable, boughtAmount).entries
+
+	settled, err := c.accountRepo.Post(ctx, entries)
+	if err != nil {
+		return err
+	}
+	if !settled {
+		return status.Errorf(codes.FailedPrecondition, "insufficient funds: %v %s required to fill order %d",
+			paidAmount, paid, order.ID)
+	}
+
+	order.Reserved -= unlock
+	return nil
+}
@@ -1,4 +1,29 @@
 package interfaces
 
+import (
+	"context"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
 type OrderUseCase interface {
+	PlaceOrder(ctx context.Context, uid int, body request.OrderRequest) (response.OrderResponse, error)
+	ListOrders(ctx context.Context, uid int) ([]utils.OrderResponse, error)
+	GetOrderByID(ctx context.Context, uid, oid uint) (utils.Order, error)
+	CancelOrder(ctx context.Context, uid, oid uint) error
+	AmendOrder(ctx context.Context, uid, oid uint, body request.AmendOrderRequest) (response.OrderResponse, error)
+
+	PlaceOrderGroup(ctx context.Context, uid int, body request.OrderGroupRequest) (utils.OrderGroupResponse, error)
+	ListOrderGroups(ctx context.Context, uid uint) ([]utils.OrderGroupResponse, error)
+	GetOrderGroup(ctx context.Context, uid, gid uint) (utils.OrderGroupResponse, error)
+	CancelOrderGroup(ctx context.Context, uid, gid uint) error
+
+	// fill the resting orders crossed by the market price, run by the matching loop
+	MatchOpenOrders(ctx context.Context) error
+	// expire the good till date orders past their expiry, run by the expiry sweeper
+	ExpireOrders(ctx context.Context) error
+	// stop out the users past their daily loss limit, run by the loss limit job
+	EnforceLossLimits(ctx context.Context) error
 }
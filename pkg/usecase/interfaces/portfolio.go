@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+)
+
+type PortfolioUseCase interface {
+	GetPositions(ctx context.Context, uid uint) ([]response.Position, error)
+	GetEquityCurve(ctx context.Context, uid uint, body request.EquityCurveRequest) ([]response.EquityPoint, error)
+	GetStats(ctx context.Context, uid uint, body request.PortfolioStatsRequest) (response.PortfolioStats, error)
+
+	// save the equity of every user, run by the equity snapshot job
+	SnapshotEquity(ctx context.Context) error
+}
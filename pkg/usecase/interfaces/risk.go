@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+type RiskUseCase interface {
+	// pre-trade risk limits of the user, their own or the defaults
+	GetRiskLimits(ctx context.Context, uid uint) (response.RiskLimits, error)
+	// admin change of the limits of the user
+	SetRiskLimits(ctx context.Context, uid uint, body request.RiskLimitsRequest) (response.RiskLimits, error)
+
+	// kill switches the daily loss of the user tripped, and the admin reset of today's one
+	ListStopOuts(ctx context.Context, uid uint) ([]utils.StopOut, error)
+	ResetStopOut(ctx context.Context, uid uint) error
+}
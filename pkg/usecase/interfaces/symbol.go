@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+type SymbolUseCase interface {
+	// symbols of the registry with their exchange filters
+	ListSymbols(ctx context.Context) ([]utils.Symbol, error)
+
+	// load the symbols of the feed or the seed file into the registry, run by the symbol refresh job
+	LoadSymbols(ctx context.Context) error
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+type WalletUseCase interface {
+	GetWallet(ctx context.Context, uid uint) (response.Wallet, error)
+	GetLedger(ctx context.Context, uid uint, asset string) ([]utils.LedgerEntry, error)
+	SetCostBasisMethod(ctx context.Context, uid uint, method string) error
+
+	// recompute the account balances from the ledger and report the drift, run by the reconciliation job
+	ReconcileLedger(ctx context.Context) error
+}
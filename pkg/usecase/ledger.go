@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+)
+
+// journal collect the ledger entries of one balance movement before they are posted
+type journal struct {
+	kind    string
+	orderID *uint
+	tradeID *uint
+	entries []domain.LedgerEntry
+}
+
+// journal of a movement, referencing the order and trade it came from when there is one
+func newJournal(kind string, orderID, tradeID *uint) *journal {
+	return &journal{kind: kind, orderID: orderID, tradeID: tradeID}
+}
+
+// debit amount to the account, or credit it when amount is negative
+func (j *journal) add(uid uint, asset, account string, amount float64) *journal {
+
+	if amount == 0 {
+		return j
+	}
+
+	entry := domain.LedgerEntry{
+		UserID:  uid,
+		Asset:   asset,
+		Account: account,
+		Kind:    j.kind,
+		OrderID: j.orderID,
+		TradeID: j.tradeID,
+	}
+	if amount > 0 {
+		entry.Debit = amount
+	} else {
+		entry.Credit = -amount
+	}
+
+	j.entries = append(j.entries, entry)
+	return j
+}
+
+// move amount from one account to the other
+func (j *journal) transfer(uid uint, asset, from, to string, amount float64) *journal {
+	return j.add(uid, asset, to, amount).add(uid, asset, from, -amount)
+}
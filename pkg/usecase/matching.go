@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+)
+
+// MatchOpenOrders trigger the pending stop and take profit orders and fill the resting limit orders
+// against the current price of their symbol. Orders of a symbol are matched in price-time priority
+// and share the liquidity of the levels of the order book. The resting orders are charged the maker fee and
+// the limit orders placed by the triggers the taker fee.
+func (c *orderUseCase) MatchOpenOrders(ctx context.Context) error {
+
+	orders, err := c.orderRepo.GetActiveOrders(ctx)
+	if err != nil {
+		return err
+	}
+
+	bySymbol := make(map[string][]domain.Order)
+	for _, order := range orders {
+		bySymbol[order.Symbol] = append(bySymbol[order.Symbol], order)
+	}
+
+	for symbol, orders := range bySymbol {
+
+		book, err := c.orderBook(ctx, symbol)
+		if err != nil {
+			log.Printf("Failed to fetch market data of %s for matching: %v", symbol, err)
+			continue
+		}
+
+		// limit orders placed by the triggers rest with the others
+		resting := make([]domain.Order, 0, len(orders))
+		triggered := make(map[uint]bool)
+		for _, order := range orders {
+			if order.Status != domain.OrderStatusPending {
+				resting = append(resting, order)
+				continue
+			}
+
+			child, err := c.triggerOrder(ctx, order, book)
+			if err != nil {
+				log.Printf("Failed to trigger order %d: %v", order.ID, err)
+				continue
+			}
+			if child != nil && child.Kind == domain.OrderKindLimit {
+				resting = append(resting, *child)
+				triggered[child.ID] = true
+			}
+		}
+
+		// IOC and FOK limits placed by the triggers are executed once here
+		sortByPriority(resting)
+		for i := range resting {
+			liquidity := domain.LiquidityMaker
+			if triggered[resting[i].ID] {
+				liquidity = domain.LiquidityTaker
+			}
+			if err := c.executeOrder(ctx, &resting[i], book, liquidity); err != nil {
+				log.Printf("Failed to match order %d: %v", resting[i].ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// fill the order up to qty if the book price cross its limit
+func (c *orderUseCase) matchOrder(ctx context.Context, order *domain.Order, book *bookLiquidity, qty float64, liquidity string) error {
+
+	qty, price, ok := book.take(order, qty)
+	if !ok {
+		return nil
+	}
+
+	return c.fillOrder(ctx, order, qty, price, liquidity)
+}
+
+// the buy orders then the sell orders, each side with the best limit first, the highest for a buy and
+// the lowest for a sell, then the oldest. the sides take different levels of the book so their order
+// to each other doesn't matter, but it has to be a key of the sort to keep each side in priority
+func sortByPriority(orders []domain.Order) {
+	sort.SliceStable(orders, func(i, j int) bool {
+		a, b := orders[i], orders[j]
+		if a.Type != b.Type {
+			return a.Type == domain.OrderSideBuy
+		}
+		if a.LimitPrice != b.LimitPrice {
+			if a.Type == domain.OrderSideBuy {
+				return a.LimitPrice > b.LimitPrice
+			}
+			return a.LimitPrice < b.LimitPrice
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
+
+// bookLiquidity is the order book left to the orders matched in one pass, the fills take the
+// quantity of the levels they cross
+type bookLiquidity struct {
+	quote marketdata.Quote
+	asks  []marketdata.DepthLevel
+	bids  []marketdata.DepthLevel
+}
+
+// the prices of the levels are rounded to the tick size of the symbol like the limit prices, the
+// depth of the synthetic and replay feeds is not on it
+func newBookLiquidity(depth marketdata.Depth, symbol domain.Symbol) *bookLiquidity {
+	return &bookLiquidity{
+		quote: depth.Quote(),
+		asks:  tickLevels(depth.Asks, symbol),
+		bids:  tickLevels(depth.Bids, symbol),
+	}
+}
+
+func tickLevels(levels []marketdata.DepthLevel, symbol domain.Symbol) []marketdata.DepthLevel {
+	rounded := make([]marketdata.DepthLevel, len(levels))
+	for i, level := range levels {
+		rounded[i] = marketdata.DepthLevel{Price: filterPrice(symbol, level.Price), Qty: level.Qty}
+	}
+	return rounded
+}
+
+// order book of the symbol for the orders placed or matched now
+func (c *orderUseCase) orderBook(ctx context.Context, symbol string) (*bookLiquidity, error) {
+
+	info, err := c.symbolRepo.GetSymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	depth, err := c.marketData.GetDepth(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch market depth: %w", err)
+	}
+	return newBookLiquidity(depth, info), nil
+}
+
+// take the quantity the order can fill up to max and its volume weighted price, and remove it from the book
+func (b *bookLiquidity) take(order *domain.Order, max float64) (qty, price float64, ok bool) {
+
+	levels := b.levels(order)
+	qty, price, used := walkLevels(*levels, order, max)
+	if qty <= 0 {
+		return 0, 0, false
+	}
+
+	// the levels used are gone, but the last one may keep part of its quantity
+	taken := 0.0
+	for _, level := range (*levels)[:used-1] {
+		taken += level.Qty
+	}
+	last := &(*levels)[used-1]
+	last.Qty -= qty - taken
+	if last.Qty > 0 {
+		used--
+	}
+	*levels = (*levels)[used:]
+
+	return qty, price, true
+}
+
+// quantity the order can fill up to max and its volume weighted price, a market order walk the levels
+// until it's filled or the book run out, a buy limit take the asks at or below it and a sell
+// limit the bids at or above it
+func (b *bookLiquidity) peek(order *domain.Order, max float64) (qty, price float64, ok bool) {
+
+	qty, price, _ = walkLevels(*b.levels(order), order, max)
+	return qty, price, qty > 0
+}
+
+// the best level of the book is at the limit of the order, it's touched but not traded through
+func (b *bookLiquidity) touched(order *domain.Order) bool {
+	levels := *b.levels(order)
+	return order.Kind == domain.OrderKindLimit && len(levels) > 0 && levels[0].Price == order.LimitPrice
+}
+
+// side of the book the order take, the asks for a buy and the bids for a sell
+func (b *bookLiquidity) levels(order *domain.Order) *[]marketdata.DepthLevel {
+	if order.Type == domain.OrderSideBuy {
+		return &b.asks
+	}
+	return &b.bids
+}
+
+// fill the rest of the order up to max across the levels, return the quantity filled, its volume
+// weighted price and the number of levels used
+func walkLevels(levels []marketdata.DepthLevel, order *domain.Order, max float64) (qty, price float64, used int) {
+
+	remaining := math.Min(order.Volume-order.FilledVolume, max)
+	if remaining <= 0 {
+		return 0, 0, 0
+	}
+
+	cost := 0.0
+	for _, level := range levels {
+		if qty >= remaining || level.Price <= 0 || level.Qty <= 0 {
+			break
+		}
+		if order.Kind != domain.OrderKindMarket && !crossesLimit(order, level.Price) {
+			break
+		}
+
+		take := math.Min(remaining-qty, level.Qty)
+		qty += take
+		cost += take * level.Price
+		used++
+	}
+
+	if qty <= 0 {
+		return 0, 0, 0
+	}
+	return qty, cost / qty, used
+}
+
+// a buy limit cross the prices at or below it and a sell limit the prices at or above it
+func crossesLimit(order *domain.Order, price float64) bool {
+	if order.Type == domain.OrderSideBuy {
+		return price <= order.LimitPrice
+	}
+	return price >= order.LimitPrice
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+)
+
+func TestSortByPriority(t *testing.T) {
+
+	at := func(seconds int) time.Time {
+		return time.Date(2024, 1, 1, 0, 0, seconds, 0, time.UTC)
+	}
+	order := func(id uint, side string, price float64, seconds int) domain.Order {
+		return domain.Order{ID: id, Type: side, Kind: domain.OrderKindLimit, LimitPrice: price, CreatedAt: at(seconds)}
+	}
+
+	tests := []struct {
+		name   string
+		orders []domain.Order
+		want   []uint
+	}{
+		{
+			name: "buys and sells interleaved by time",
+			orders: []domain.Order{
+				order(1, domain.OrderSideBuy, 100, 1),
+				order(2, domain.OrderSideSell, 105, 2),
+				order(3, domain.OrderSideBuy, 102, 3),
+				order(4, domain.OrderSideSell, 103, 4),
+				order(5, domain.OrderSideBuy, 101, 5),
+				order(6, domain.OrderSideSell, 104, 6),
+			},
+			want: []uint{3, 5, 1, 4, 6, 2},
+		},
+		{
+			name: "same price by time",
+			orders: []domain.Order{
+				order(1, domain.OrderSideSell, 105, 3),
+				order(2, domain.OrderSideBuy, 100, 4),
+				order(3, domain.OrderSideSell, 105, 1),
+				order(4, domain.OrderSideBuy, 100, 2),
+				order(5, domain.OrderSideBuy, 99, 0),
+			},
+			want: []uint{4, 2, 5, 3, 1},
+		},
+		{
+			name: "a sell older than a worse buy",
+			orders: []domain.Order{
+				order(1, domain.OrderSideBuy, 99, 3),
+				order(2, domain.OrderSideSell, 110, 2),
+				order(3, domain.OrderSideBuy, 101, 1),
+			},
+			want: []uint{3, 1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortByPriority(tt.orders)
+
+			got := make([]uint, len(tt.orders))
+			for i, order := range tt.orders {
+				got[i] = order.ID
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBookTouchedOnTick(t *testing.T) {
+
+	symbol := domain.Symbol{Symbol: "BTCUSDT", TickSize: 0.01}
+	depth := marketdata.Depth{
+		Symbol: "BTCUSDT",
+		Bids:   []marketdata.DepthLevel{{Price: 99.99731, Qty: 1}},
+		Asks:   []marketdata.DepthLevel{{Price: 100.00412, Qty: 1}, {Price: 100.01388, Qty: 2}},
+	}
+
+	tests := []struct {
+		name    string
+		order   domain.Order
+		touched bool
+		fill    float64
+	}{
+		{
+			name:    "buy at the rounded best ask",
+			order:   domain.Order{Type: domain.OrderSideBuy, Kind: domain.OrderKindLimit, LimitPrice: 100, Volume: 3},
+			touched: true,
+			fill:    1,
+		},
+		{
+			name:  "buy through the best ask",
+			order: domain.Order{Type: domain.OrderSideBuy, Kind: domain.OrderKindLimit, LimitPrice: 100.01, Volume: 3},
+			fill:  3,
+		},
+		{
+			name:    "sell at the rounded best bid",
+			order:   domain.Order{Type: domain.OrderSideSell, Kind: domain.OrderKindLimit, LimitPrice: 100, Volume: 1},
+			touched: true,
+			fill:    1,
+		},
+		{
+			name:  "sell above the best bid",
+			order: domain.Order{Type: domain.OrderSideSell, Kind: domain.OrderKindLimit, LimitPrice: 100.01, Volume: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := newBookLiquidity(depth, symbol)
+
+			if touched := book.touched(&tt.order); touched != tt.touched {
+				t.Errorf("touched %v, want %v", touched, tt.touched)
+			}
+			if qty, _, _ := book.peek(&tt.order, tt.order.Volume); qty != tt.fill {
+				t.Errorf("fill %v, want %v", qty, tt.fill)
+			}
+		})
+	}
+}
@@ -1,16 +1,636 @@
 package usecase
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
 	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/notify"
 	service "github.com/kannan112/mock-trading-platform-api/pkg/usecase/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type orderUseCase struct {
-	orderRepo interfaces.OrderRepository
+	orderRepo   interfaces.OrderRepository
+	accountRepo interfaces.AccountRepository
+	tradeRepo   interfaces.TradeRepository
+	userRepo    interfaces.UserRepository
+	symbolRepo  interfaces.SymbolRepository
+	riskRepo    interfaces.RiskRepository
+	// equity snapshots the daily loss is counted from
+	portfolioRepo interfaces.PortfolioRepository
+	marketData    marketdata.MarketDataProvider
+	fees          feeSchedule
+	// risk limits of the users without their own
+	riskDefaults domain.RiskLimits
+	notifier     notify.Notifier
+	// fill market orders partially when the book run out
+	partialFills bool
+	execution    *executionSim
 }
 
-func NewOrderUseCase(orderRepo interfaces.OrderRepository) service.OrderUseCase {
+func NewOrderUseCase(cfg config.Config, orderRepo interfaces.OrderRepository, accountRepo interfaces.AccountRepository,
+	tradeRepo interfaces.TradeRepository, userRepo interfaces.UserRepository, symbolRepo interfaces.SymbolRepository,
+	riskRepo interfaces.RiskRepository, portfolioRepo interfaces.PortfolioRepository, marketData marketdata.MarketDataProvider,
+	notifier notify.Notifier) (service.OrderUseCase, error) {
+
+	fees, err := newFeeSchedule(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &orderUseCase{
-		orderRepo: orderRepo,
+		orderRepo:     orderRepo,
+		accountRepo:   accountRepo,
+		tradeRepo:     tradeRepo,
+		userRepo:      userRepo,
+		symbolRepo:    symbolRepo,
+		riskRepo:      riskRepo,
+		portfolioRepo: portfolioRepo,
+		marketData:    marketData,
+		fees:          fees,
+		riskDefaults:  riskDefaults(cfg),
+		notifier:      notifier,
+		partialFills:  cfg.OrderPartialFills,
+		execution:     newExecutionSim(cfg),
+	}, nil
+}
+
+// PlaceOrder fill a market order at the current price, a limit order is filled as much as the
+// current price allow and the rest of it stay open for the matching loop. Stop and take profit
+// orders stay pending until the matching loop see their trigger price.
+// The funds of the order are reserved in the same transaction, quote asset for a buy and base asset for a sell,
+// and the fills placing it are charged the taker fee.
+func (c *orderUseCase) PlaceOrder(ctx context.Context, uid int, body request.OrderRequest) (response.OrderResponse, error) {
+
+	side := strings.ToLower(body.Type)
+	if side != domain.OrderSideBuy && side != domain.OrderSideSell {
+		return response.OrderResponse{}, fmt.Errorf("invalid order type: %s", body.Type)
+	}
+
+	kind := body.Kind
+	if kind == "" {
+		kind = domain.OrderKindMarket
+	}
+	if (kind == domain.OrderKindLimit || kind == domain.OrderKindStopLimit) && body.LimitPrice <= 0 {
+		return response.OrderResponse{}, fmt.Errorf("limit price is required for %s orders", kind)
+	}
+	if isTriggerKind(kind) && kind != domain.OrderKindTrailingStop && body.TriggerPrice <= 0 {
+		return response.OrderResponse{}, fmt.Errorf("trigger price is required for %s orders", kind)
+	}
+	if kind == domain.OrderKindTrailingStop && (body.TrailingOffset > 0) == (body.TrailingPercent > 0) {
+		return response.OrderResponse{}, fmt.Errorf("either a trailing offset or a trailing percent is required for trailing stop orders")
+	}
+
+	timeInForce := body.TimeInForce
+	if timeInForce == "" {
+		timeInForce = domain.TimeInForceGTC
+	}
+	if timeInForce == domain.TimeInForceGTD && (body.ExpiresAt == nil || !body.ExpiresAt.After(time.Now())) {
+		return response.OrderResponse{}, fmt.Errorf("a future expiry time is required for GTD orders")
+	}
+	expiresAt := body.ExpiresAt
+	if timeInForce != domain.TimeInForceGTD {
+		expiresAt = nil
+	}
+
+	// quantity and prices are rounded to the filters of the symbol
+	symbol, err := resolveSymbol(ctx, c.symbolRepo, body.Symbol)
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+	volume, err := filterVolume(symbol, body.Volume)
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+
+	if err := c.submitOrder(ctx); err != nil {
+		return response.OrderResponse{}, err
+	}
+
+	book, err := c.orderBook(ctx, symbol.Symbol)
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+	quote := book.quote
+
+	order := domain.Order{
+		OrderUUID:   uuid.New().String(),
+		UserID:      uint(uid),
+		Symbol:      symbol.Symbol,
+		Volume:      volume,
+		Type:        side,
+		Kind:        kind,
+		LimitPrice:  filterPrice(symbol, body.LimitPrice),
+		Status:      domain.OrderStatusOpen,
+		TimeInForce: timeInForce,
+		ExpiresAt:   expiresAt,
+	}
+
+	if kind == domain.OrderKindTrailingStop {
+		order.TrailingOffset = filterPrice(symbol, body.TrailingOffset)
+		order.TrailingPercent = body.TrailingPercent
+		order.Status = domain.OrderStatusPending
+
+		// start trailing from the current price
+		order.BestPrice, err = marketPrice(quote, side)
+		if err != nil {
+			return response.OrderResponse{}, err
+		}
+		order.TriggerPrice = filterPrice(symbol, trailingTrigger(order, order.BestPrice))
+	} else if isTriggerKind(kind) {
+		order.TriggerPrice = filterPrice(symbol, body.TriggerPrice)
+		order.Status = domain.OrderStatusPending
+
+		// like the exchange, refuse a trigger the market has already reached
+		if isTriggered(order, quote) {
+			return response.OrderResponse{}, fmt.Errorf("trigger price %v of the %s order would trigger immediately", order.TriggerPrice, kind)
+		}
+	}
+
+	price, err := reservePrice(order, book)
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+	if err := checkNotional(symbol, order.Volume, price); err != nil {
+		return response.OrderResponse{}, err
+	}
+
+	err = c.orderRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := c.checkRisk(ctx, riskCheck{
+			uid:         order.UserID,
+			symbol:      order.Symbol,
+			side:        side,
+			volume:      order.Volume,
+			remaining:   order.Volume,
+			price:       price,
+			limitPrices: []float64{order.LimitPrice},
+			mark:        markPrice(quote),
+			newOrders:   1,
+		}); err != nil {
+			return err
+		}
+
+		if err := c.createReservedOrder(ctx, &order, c.reservation(order, price)); err != nil {
+			return err
+		}
+		return c.executeOrder(ctx, &order, book, domain.LiquidityTaker)
+	})
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+
+	return toOrderResponse(order), nil
+}
+
+// save the new order and set its ID
+func (c *orderUseCase) createOrder(ctx context.Context, order *domain.Order) error {
+
+	if order.TimeInForce == "" {
+		order.TimeInForce = domain.TimeInForceGTC
+	}
+
+	oid, err := c.orderRepo.PlaceOrder(ctx, *order)
+	if err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+	order.ID = oid
+	return nil
+}
+
+// fill a market order across the levels of the book and a limit order as much as the book cross its limit,
+// the other kinds wait for the matching loop. a FOK limit is cancelled unless the book fill all of it
+// and the unfilled rest of an IOC limit is cancelled. a market order always take liquidity.
+func (c *orderUseCase) executeOrder(ctx context.Context, order *domain.Order, book *bookLiquidity, liquidity string) error {
+
+	switch order.Kind {
+	case domain.OrderKindMarket:
+		return c.executeMarketOrder(ctx, order, book)
+	case domain.OrderKindLimit:
+		// an order the book only touch may fill part of what's at its price
+		fillable := order.Volume - order.FilledVolume
+		if book.touched(order) {
+			fillable = c.execution.touchFill(fillable)
+		}
+
+		if order.TimeInForce == domain.TimeInForceFOK {
+			if qty, _, ok := book.peek(order, fillable); !ok || qty < order.Volume-order.FilledVolume {
+				return c.cancelOrder(ctx, order, domain.OrderEndFOKUnfilled)
+			}
+		}
+		if err := c.matchOrder(ctx, order, book, fillable, liquidity); err != nil {
+			return err
+		}
+		if order.TimeInForce == domain.TimeInForceIOC && order.Status != domain.OrderStatusFilled {
+			return c.cancelOrder(ctx, order, domain.OrderEndIOCUnfilled)
+		}
+	}
+	return nil
+}
+
+// fill the market order across the levels of the book, when the book run out the rest of the order is
+// cancelled with partial fills and the order rejected without them
+func (c *orderUseCase) executeMarketOrder(ctx context.Context, order *domain.Order, book *bookLiquidity) error {
+
+	remaining := order.Volume - order.FilledVolume
+	if qty, _, ok := book.peek(order, remaining); !c.partialFills && (!ok || qty < remaining) {
+		return status.Errorf(codes.FailedPrecondition, "not enough liquidity to fill %v %s, %v in the order book",
+			remaining, order.Symbol, qty)
+	}
+
+	if qty, price, ok := book.take(order, remaining); ok {
+		if err := c.fillOrder(ctx, order, qty, price, domain.LiquidityTaker); err != nil {
+			return err
+		}
+	}
+	if order.Status != domain.OrderStatusFilled {
+		return c.cancelOrder(ctx, order, domain.OrderEndLiquidityExhausted)
+	}
+	return nil
+}
+
+// cancel the live order for the reason and release the funds it reserved
+func (c *orderUseCase) cancelOrder(ctx context.Context, order *domain.Order, reason string) error {
+
+	return c.orderRepo.Transaction(ctx, func(ctx context.Context) error {
+		cancelled, err := c.orderRepo.CancelOrder(ctx, order.ID, reason)
+		if err != nil {
+			return err
+		}
+		if !cancelled {
+			return fmt.Errorf("order %d already ended", order.ID)
+		}
+		if err := c.releaseFunds(ctx, order); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		order.Status = domain.OrderStatusCancelled
+		order.EndReason = reason
+		order.CancelledAt = &now
+		return c.endGroupEntry(ctx, *order, reason)
+	})
+}
+
+// ExpireOrders expire the good till date orders past their expiry and release their funds,
+// run by the expiry sweeper
+func (c *orderUseCase) ExpireOrders(ctx context.Context) error {
+
+	orders, err := c.orderRepo.GetExpiredOrders(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	expired := 0
+	for i := range orders {
+		order := &orders[i]
+		err := c.orderRepo.Transaction(ctx, func(ctx context.Context) error {
+			ok, err := c.orderRepo.ExpireOrder(ctx, order.ID)
+			if err != nil || !ok {
+				return err
+			}
+			expired++
+			if err := c.releaseFunds(ctx, order); err != nil {
+				return err
+			}
+			return c.endGroupEntry(ctx, *order, domain.OrderEndExpired)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to expire order %d: %w", order.ID, err)
+		}
+	}
+
+	if expired > 0 {
+		log.Printf("Expired %d good till date orders", expired)
+	}
+	return nil
+}
+
+// ListOrders return the orders of the user with their trades and the lots each trade closed
+func (c *orderUseCase) ListOrders(ctx context.Context, uid int) ([]utils.OrderResponse, error) {
+
+	data, err := c.orderRepo.GetAllOrders(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	trades, err := c.tradeRepo.GetUserTrades(ctx, uint(uid))
+	if err != nil {
+		return nil, err
+	}
+	matches, err := c.tradeRepo.GetUserLotMatches(ctx, uint(uid))
+	if err != nil {
+		return nil, err
+	}
+
+	lotsByTrade := make(map[uint][]utils.LotMatch)
+	for _, match := range matches {
+		lotsByTrade[match.TradeID] = append(lotsByTrade[match.TradeID], match)
+	}
+	tradesByOrder := make(map[uint][]utils.Trade)
+	for _, trade := range trades {
+		trade.Lots = lotsByTrade[trade.ID]
+		tradesByOrder[trade.OrderID] = append(tradesByOrder[trade.OrderID], trade)
+	}
+
+	for i := range data {
+		data[i].Trades = tradesByOrder[data[i].OrderID]
+	}
+	return data, nil
+}
+
+// GetOrderByID return the order with the orders it placed when triggered and its amendments
+func (c *orderUseCase) GetOrderByID(ctx context.Context, uid, oid uint) (utils.Order, error) {
+	data, err := c.orderRepo.GetOrderByID(oid, uid)
+	if err != nil {
+		return utils.Order{}, err
+	}
+
+	data.Children, err = c.orderRepo.GetChildOrders(ctx, data.ID)
+	if err != nil {
+		return utils.Order{}, err
+	}
+
+	data.Amendments, err = c.orderRepo.GetOrderAmendments(ctx, data.ID)
+	return data, err
+}
+
+// CancelOrder cancel a live order and keep it in the history, cancelling a leg of an
+// active group cancel the whole group
+func (c *orderUseCase) CancelOrder(ctx context.Context, uid, oid uint) error {
+
+	order, err := c.orderRepo.GetOrder(ctx, oid, uid)
+	if err != nil {
+		return err
+	}
+	if !isLiveOrder(order) {
+		return fmt.Errorf("order %d is %s and can't be cancelled", oid, order.Status)
+	}
+
+	// cancelling a bracket entry end the entry alone, the exits cover what it filled
+	if order.GroupID != nil && order.GroupLeg != domain.OrderLegEntry {
+		cancelled, err := c.cancelOrderGroup(ctx, *order.GroupID, uid)
+		if err != nil || cancelled {
+			return err
+		}
+	}
+
+	return c.cancelOrder(ctx, &order, domain.OrderEndUserCancelled)
+}
+
+// AmendOrder change the quantity or prices of a resting order and record the amendment,
+// the funds reserved follow the new quantity and price and an amended limit order is matched again right away
+func (c *orderUseCase) AmendOrder(ctx context.Context, uid, oid uint, body request.AmendOrderRequest) (response.OrderResponse, error) {
+
+	if body.Volume == 0 && body.LimitPrice == 0 && body.TriggerPrice == 0 {
+		return response.OrderResponse{}, fmt.Errorf("nothing to amend")
+	}
+
+	order, err := c.orderRepo.GetOrder(ctx, oid, uid)
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+	if !isLiveOrder(order) {
+		return response.OrderResponse{}, fmt.Errorf("order %d is %s and can't be amended", oid, order.Status)
+	}
+	if order.GroupID != nil {
+		return response.OrderResponse{}, fmt.Errorf("order %d is part of order group %d, cancel the group instead", oid, *order.GroupID)
+	}
+
+	amendment := domain.OrderAmendment{
+		OrderID:         order.ID,
+		UserID:          order.UserID,
+		OldVolume:       order.Volume,
+		OldLimitPrice:   order.LimitPrice,
+		OldTriggerPrice: order.TriggerPrice,
+	}
+
+	symbol, err := c.symbolRepo.GetSymbol(ctx, order.Symbol)
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+
+	if body.Volume > 0 {
+		volume, err := filterVolume(symbol, body.Volume)
+		if err != nil {
+			return response.OrderResponse{}, err
+		}
+		if volume <= order.FilledVolume {
+			return response.OrderResponse{}, fmt.Errorf("volume must be more than the filled volume %v", order.FilledVolume)
+		}
+		order.Volume = volume
+	}
+	if body.LimitPrice > 0 {
+		if order.Kind != domain.OrderKindLimit && order.Kind != domain.OrderKindStopLimit {
+			return response.OrderResponse{}, fmt.Errorf("%s orders have no limit price", order.Kind)
+		}
+		order.LimitPrice = filterPrice(symbol, body.LimitPrice)
+	}
+	if body.TriggerPrice > 0 {
+		if !isTriggerKind(order.Kind) || order.Kind == domain.OrderKindTrailingStop {
+			return response.OrderResponse{}, fmt.Errorf("the trigger price of %s orders can't be amended", order.Kind)
+		}
+		order.TriggerPrice = filterPrice(symbol, body.TriggerPrice)
+	}
+
+	amendment.NewVolume = order.Volume
+	amendment.NewLimitPrice = order.LimitPrice
+	amendment.NewTriggerPrice = order.TriggerPrice
+
+	book, err := c.orderBook(ctx, order.Symbol)
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+	if order.Status == domain.OrderStatusPending && isTriggered(order, book.quote) {
+		return response.OrderResponse{}, fmt.Errorf("trigger price %v of the %s order would trigger immediately", order.TriggerPrice, order.Kind)
+	}
+
+	price, err := reservePrice(order, book)
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+	if err := checkNotional(symbol, order.Volume, price); err != nil {
+		return response.OrderResponse{}, err
+	}
+
+	err = c.orderRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := c.checkRisk(ctx, riskCheck{
+			uid:         order.UserID,
+			symbol:      order.Symbol,
+			side:        order.Type,
+			volume:      order.Volume,
+			remaining:   order.Volume - order.FilledVolume,
+			price:       price,
+			limitPrices: []float64{order.LimitPrice},
+			mark:        markPrice(book.quote),
+		}); err != nil {
+			return err
+		}
+
+		// reserve the difference, or release it when the order now need less
+		needed := c.reservation(order, price)
+		if needed > order.Reserved {
+			if err := c.reserveFunds(ctx, &order, needed-order.Reserved); err != nil {
+				return err
+			}
+		} else if needed < order.Reserved {
+			surplus := order
+			surplus.Reserved = order.Reserved - needed
+			if err := c.releaseFunds(ctx, &surplus); err != nil {
+				return err
+			}
+			order.Reserved = needed
+		}
+
+		amended, err := c.orderRepo.AmendOrder(ctx, order, amendment)
+		if err != nil {
+			return err
+		}
+		if !amended {
+			return fmt.Errorf("order %d changed while amending", oid)
+		}
+
+		if order.Kind == domain.OrderKindLimit {
+			return c.executeOrder(ctx, &order, book, domain.LiquidityTaker)
+		}
+		return nil
+	})
+	if err != nil {
+		return response.OrderResponse{}, err
+	}
+
+	return toOrderResponse(order), nil
+}
+
+// orders still waiting to trigger or fill
+func isLiveOrder(order domain.Order) bool {
+	switch order.Status {
+	case domain.OrderStatusPending, domain.OrderStatusOpen, domain.OrderStatusPartiallyFilled:
+		return true
+	}
+	return false
+}
+
+// fill qty of the order at price as a trade, settle it against the funds reserved, charge the fee of
+// the liquidity, update the position and save the order, the order price is kept as the average fill price.
+// an exit leg of a group cancel its sibling before filling and a filled bracket entry place the exits.
+func (c *orderUseCase) fillOrder(ctx context.Context, order *domain.Order, qty, price float64, liquidity string) error {
+
+	filled := *order
+	err := c.orderRepo.Transaction(ctx, func(ctx context.Context) error {
+		claimed, err := c.claimGroupLeg(ctx, &filled)
+		if err != nil || !claimed {
+			return err
+		}
+
+		rate, err := c.feeRate(ctx, filled.UserID, liquidity)
+		if err != nil {
+			return err
+		}
+		trade, err := c.recordTrade(ctx, filled, qty, price, liquidity, rate)
+		if err != nil {
+			return err
+		}
+		if err := c.settleFill(ctx, &filled, trade); err != nil {
+			return err
+		}
+		if err := c.chargeFee(ctx, &filled, trade); err != nil {
+			return err
+		}
+
+		prevFilledVolume := order.FilledVolume
+
+		filled.FilledVolume = prevFilledVolume + qty
+		filled.Price = (order.Price*prevFilledVolume + price*qty) / filled.FilledVolume
+
+		if filled.FilledVolume >= filled.Volume {
+			now := time.Now()
+			filled.FilledVolume = filled.Volume
+			filled.Status = domain.OrderStatusFilled
+			filled.EndReason = domain.OrderEndFilled
+			filled.FilledAt = &now
+		} else {
+			filled.Status = domain.OrderStatusPartiallyFilled
+		}
+
+		updated, err := c.orderRepo.UpdateOrderFill(ctx, filled, prevFilledVolume)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return fmt.Errorf("order %d changed while filling", order.ID)
+		}
+
+		if filled.Status == domain.OrderStatusFilled && filled.GroupLeg == domain.OrderLegEntry {
+			return c.placeExitLegs(ctx, *filled.GroupID, filled.UserID, filled.FilledVolume)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*order = filled
+	return nil
+}
+
+// market price of an order side, buy at the ask and sell at the bid
+func marketPrice(marketData marketdata.Quote, orderType string) (float64, error) {
+	// Convert orderType to lowercase for case-insensitive comparison
+	orderType = strings.ToLower(orderType)
+
+	switch orderType {
+	case domain.OrderSideBuy:
+		if marketData.AskPrice <= 0 {
+			return 0, fmt.Errorf("invalid ask price: %v", marketData.AskPrice)
+		}
+		return marketData.AskPrice, nil
+	case domain.OrderSideSell:
+		if marketData.BidPrice <= 0 {
+			return 0, fmt.Errorf("invalid bid price: %v", marketData.BidPrice)
+		}
+		return marketData.BidPrice, nil
+	default:
+		return 0, fmt.Errorf("invalid order type: %s", orderType)
+	}
+}
+
+func toOrderResponse(order domain.Order) response.OrderResponse {
+	return response.OrderResponse{
+		OrderID:         order.ID,
+		OrderUUID:       order.OrderUUID,
+		Symbol:          order.Symbol,
+		Volume:          order.Volume,
+		Price:           order.Price,
+		Type:            order.Type,
+		Kind:            order.Kind,
+		LimitPrice:      order.LimitPrice,
+		TriggerPrice:    order.TriggerPrice,
+		TrailingOffset:  order.TrailingOffset,
+		TrailingPercent: order.TrailingPercent,
+		BestPrice:       order.BestPrice,
+		FilledVolume:    order.FilledVolume,
+		Reserved:        order.Reserved,
+		Fee:             order.Fee,
+		Status:          order.Status,
+		TimeInForce:     order.TimeInForce,
+		ExpiresAt:       order.ExpiresAt,
+		EndReason:       order.EndReason,
+		ParentID:        order.ParentOrderID,
+		GroupID:         order.GroupID,
+		GroupLeg:        order.GroupLeg,
+		TriggeredAt:     order.TriggeredAt,
+		FilledAt:        order.FilledAt,
 	}
 }
@@ -0,0 +1,369 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+// PlaceOrderGroup place a bracket entry, its stop and target follow once it's filled, or for the part
+// it filled when it end before, or the stop and target of an OCO right away. The funds of the group are reserved in the same transaction.
+func (c *orderUseCase) PlaceOrderGroup(ctx context.Context, uid int, body request.OrderGroupRequest) (utils.OrderGroupResponse, error) {
+
+	side := strings.ToLower(body.Type)
+	if side != domain.OrderSideBuy && side != domain.OrderSideSell {
+		return utils.OrderGroupResponse{}, fmt.Errorf("invalid order type: %s", body.Type)
+	}
+
+	// quantity and prices are rounded to the filters of the symbol
+	symbol, err := resolveSymbol(ctx, c.symbolRepo, body.Symbol)
+	if err != nil {
+		return utils.OrderGroupResponse{}, err
+	}
+	volume, err := filterVolume(symbol, body.Volume)
+	if err != nil {
+		return utils.OrderGroupResponse{}, err
+	}
+
+	group := domain.OrderGroup{
+		GroupUUID:       uuid.New().String(),
+		UserID:          uint(uid),
+		Type:            body.GroupType,
+		Symbol:          symbol.Symbol,
+		Side:            side,
+		Volume:          volume,
+		TakeProfitPrice: filterPrice(symbol, body.TakeProfitPrice),
+		StopPrice:       filterPrice(symbol, body.StopPrice),
+		StopLimitPrice:  filterPrice(symbol, body.StopLimitPrice),
+		Status:          domain.OrderGroupStatusActive,
+	}
+	entryPrice := filterPrice(symbol, body.EntryPrice)
+
+	entryKind := body.EntryKind
+	if entryKind == "" {
+		entryKind = domain.OrderKindMarket
+	}
+
+	switch group.Type {
+	case domain.OrderGroupBracket:
+		// the exits close the position the entry open
+		group.Side = oppositeSide(side)
+		if entryKind == domain.OrderKindLimit && entryPrice <= 0 {
+			return utils.OrderGroupResponse{}, fmt.Errorf("entry price is required for limit entries")
+		}
+	case domain.OrderGroupOCO:
+	default:
+		return utils.OrderGroupResponse{}, fmt.Errorf("invalid order group type: %s", body.GroupType)
+	}
+
+	// a sell exit take profit above the stop and a buy exit below it
+	if group.Side == domain.OrderSideSell && group.TakeProfitPrice <= group.StopPrice ||
+		group.Side == domain.OrderSideBuy && group.TakeProfitPrice >= group.StopPrice {
+		return utils.OrderGroupResponse{}, fmt.Errorf("take profit price %v and stop price %v are on the wrong side for %s exits",
+			group.TakeProfitPrice, group.StopPrice, group.Side)
+	}
+
+	// every leg of the group fill at its lowest price at worst
+	lowest := math.Min(group.TakeProfitPrice, group.StopPrice)
+	for _, price := range []float64{group.StopLimitPrice, entryPrice} {
+		if price > 0 {
+			lowest = math.Min(lowest, price)
+		}
+	}
+	if err := checkNotional(symbol, group.Volume, lowest); err != nil {
+		return utils.OrderGroupResponse{}, err
+	}
+
+	if err := c.submitOrder(ctx); err != nil {
+		return utils.OrderGroupResponse{}, err
+	}
+
+	book, err := c.orderBook(ctx, group.Symbol)
+	if err != nil {
+		return utils.OrderGroupResponse{}, err
+	}
+
+	if group.Type == domain.OrderGroupOCO && isTriggered(exitStopLeg(group), book.quote) {
+		return utils.OrderGroupResponse{}, fmt.Errorf("stop price %v of the OCO would trigger immediately", group.StopPrice)
+	}
+
+	// the risk of the group is the one of its legs at their highest price, with the two legs live at once
+	highest := math.Max(group.TakeProfitPrice, math.Max(group.StopPrice, math.Max(group.StopLimitPrice, entryPrice)))
+	if group.Type == domain.OrderGroupBracket && entryKind == domain.OrderKindMarket {
+		price, err := marketPrice(book.quote, side)
+		if err != nil {
+			return utils.OrderGroupResponse{}, err
+		}
+		highest = math.Max(highest, price)
+	}
+	err = c.orderRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := c.checkRisk(ctx, riskCheck{
+			uid:         group.UserID,
+			symbol:      group.Symbol,
+			side:        side,
+			volume:      group.Volume,
+			remaining:   group.Volume,
+			price:       highest,
+			limitPrices: []float64{group.TakeProfitPrice, group.StopLimitPrice, entryPrice},
+			mark:        markPrice(book.quote),
+			newOrders:   2,
+		}); err != nil {
+			return err
+		}
+
+		gid, err := c.orderRepo.CreateOrderGroup(ctx, group)
+		if err != nil {
+			return err
+		}
+		group.ID = gid
+
+		if group.Type == domain.OrderGroupOCO {
+			return c.placeExitLegs(ctx, group.ID, group.UserID, group.Volume)
+		}
+
+		entry := domain.Order{
+			OrderUUID:  uuid.New().String(),
+			UserID:     group.UserID,
+			Symbol:     group.Symbol,
+			Volume:     group.Volume,
+			Type:       side,
+			Kind:       entryKind,
+			LimitPrice: entryPrice,
+			Status:     domain.OrderStatusOpen,
+			GroupID:    &group.ID,
+			GroupLeg:   domain.OrderLegEntry,
+		}
+		price, err := reservePrice(entry, book)
+		if err != nil {
+			return err
+		}
+		if err := c.createReservedOrder(ctx, &entry, c.reservation(entry, price)); err != nil {
+			return err
+		}
+		return c.executeOrder(ctx, &entry, book, domain.LiquidityTaker)
+	})
+	if err != nil {
+		return utils.OrderGroupResponse{}, err
+	}
+
+	return c.GetOrderGroup(ctx, group.UserID, group.ID)
+}
+
+func (c *orderUseCase) ListOrderGroups(ctx context.Context, uid uint) ([]utils.OrderGroupResponse, error) {
+
+	groups, err := c.orderRepo.GetOrderGroups(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]utils.OrderGroupResponse, len(groups))
+	for i, group := range groups {
+		if data[i], err = c.toOrderGroupResponse(ctx, group); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (c *orderUseCase) GetOrderGroup(ctx context.Context, uid, gid uint) (utils.OrderGroupResponse, error) {
+
+	group, err := c.orderRepo.GetOrderGroup(ctx, gid, uid)
+	if err != nil {
+		return utils.OrderGroupResponse{}, err
+	}
+	return c.toOrderGroupResponse(ctx, group)
+}
+
+// CancelOrderGroup cancel every order of the group still live
+func (c *orderUseCase) CancelOrderGroup(ctx context.Context, uid, gid uint) error {
+
+	group, err := c.orderRepo.GetOrderGroup(ctx, gid, uid)
+	if err != nil {
+		return err
+	}
+
+	cancelled, err := c.cancelOrderGroup(ctx, group.ID, uid)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return fmt.Errorf("order group %d is already %s", gid, group.Status)
+	}
+	return nil
+}
+
+// cancel the active group and its live orders, releasing their funds. false when the group is no longer active
+func (c *orderUseCase) cancelOrderGroup(ctx context.Context, gid, uid uint) (bool, error) {
+
+	cancelled := false
+	err := c.orderRepo.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		cancelled, err = c.orderRepo.CancelOrderGroup(ctx, gid, uid)
+		if err != nil || !cancelled {
+			return err
+		}
+
+		legs, err := c.orderRepo.GetLiveGroupOrders(ctx, gid)
+		if err != nil {
+			return err
+		}
+		for i := range legs {
+			if err := c.cancelOrder(ctx, &legs[i], domain.OrderEndGroupCancelled); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return cancelled, err
+}
+
+// place the stop and target of the group for volume, the matching loop watch them from there.
+// only one of them can fill, so the funds of the exit are reserved on the stop and move to
+// whichever leg claim the group. a buy exit reserve at the higher of its prices.
+func (c *orderUseCase) placeExitLegs(ctx context.Context, gid, uid uint, volume float64) error {
+
+	group, err := c.orderRepo.GetOrderGroup(ctx, gid, uid)
+	if err != nil {
+		return err
+	}
+	if group.Status != domain.OrderGroupStatusActive {
+		return nil
+	}
+
+	stop := exitStopLeg(group)
+	stop.Volume = volume
+	target := domain.Order{
+		OrderUUID:  uuid.New().String(),
+		UserID:     group.UserID,
+		Symbol:     group.Symbol,
+		Volume:     volume,
+		Type:       group.Side,
+		Kind:       domain.OrderKindLimit,
+		LimitPrice: group.TakeProfitPrice,
+		Status:     domain.OrderStatusOpen,
+		GroupID:    &group.ID,
+		GroupLeg:   domain.OrderLegTarget,
+	}
+
+	price := math.Max(stop.TriggerPrice, target.LimitPrice)
+	if stop.Kind == domain.OrderKindStopLimit {
+		price = math.Max(stop.LimitPrice, target.LimitPrice)
+	}
+	if err := c.createReservedOrder(ctx, &stop, c.reservation(stop, price)); err != nil {
+		return fmt.Errorf("failed to place the stop of order group %d: %w", gid, err)
+	}
+	if err := c.createOrder(ctx, &target); err != nil {
+		return fmt.Errorf("failed to place the target of order group %d: %w", gid, err)
+	}
+	return nil
+}
+
+// a bracket entry ended before it filled, the exits are placed for the part it filled and the group
+// is cancelled when it filled nothing. an entry ended with its group or by a stop out leave the group be
+func (c *orderUseCase) endGroupEntry(ctx context.Context, entry domain.Order, reason string) error {
+
+	if entry.GroupID == nil || entry.GroupLeg != domain.OrderLegEntry {
+		return nil
+	}
+	switch reason {
+	case domain.OrderEndGroupCancelled, domain.OrderEndStopOut:
+		return nil
+	}
+
+	if entry.FilledVolume > 0 {
+		return c.placeExitLegs(ctx, *entry.GroupID, entry.UserID, entry.FilledVolume)
+	}
+	_, err := c.orderRepo.CancelOrderGroup(ctx, *entry.GroupID, entry.UserID)
+	return err
+}
+
+// claim the group for an exit leg about to fill or trigger, cancelling its sibling and taking over
+// the funds it reserved. false when the sibling got there first, orders out of a group are always claimed.
+func (c *orderUseCase) claimGroupLeg(ctx context.Context, order *domain.Order) (bool, error) {
+
+	if order.GroupID == nil || order.GroupLeg == domain.OrderLegEntry {
+		return true, nil
+	}
+
+	claimed, err := c.orderRepo.ClaimOrderGroup(ctx, *order.GroupID, order.ID)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	legs, err := c.orderRepo.GetLiveGroupOrders(ctx, *order.GroupID)
+	if err != nil {
+		return false, err
+	}
+	for _, leg := range legs {
+		if leg.ID == order.ID {
+			continue
+		}
+		cancelled, err := c.orderRepo.CancelOrder(ctx, leg.ID, domain.OrderEndSiblingFilled)
+		if err != nil {
+			return false, err
+		}
+		if cancelled {
+			order.Reserved += leg.Reserved
+		}
+	}
+	return true, nil
+}
+
+func (c *orderUseCase) toOrderGroupResponse(ctx context.Context, group domain.OrderGroup) (utils.OrderGroupResponse, error) {
+
+	orders, err := c.orderRepo.GetGroupOrders(ctx, group.ID)
+	if err != nil {
+		return utils.OrderGroupResponse{}, err
+	}
+
+	return utils.OrderGroupResponse{
+		GroupID:         group.ID,
+		GroupUUID:       group.GroupUUID,
+		Type:            group.Type,
+		Symbol:          group.Symbol,
+		Side:            group.Side,
+		Volume:          group.Volume,
+		TakeProfitPrice: group.TakeProfitPrice,
+		StopPrice:       group.StopPrice,
+		StopLimitPrice:  group.StopLimitPrice,
+		Status:          group.Status,
+		FilledOrderID:   group.FilledOrderID,
+		CreatedAt:       group.CreatedAt,
+		Orders:          orders,
+	}, nil
+}
+
+// stop leg of the group, a stop limit when the group has a stop limit price
+func exitStopLeg(group domain.OrderGroup) domain.Order {
+
+	stop := domain.Order{
+		OrderUUID:    uuid.New().String(),
+		UserID:       group.UserID,
+		Symbol:       group.Symbol,
+		Volume:       group.Volume,
+		Type:         group.Side,
+		Kind:         domain.OrderKindStopMarket,
+		TriggerPrice: group.StopPrice,
+		Status:       domain.OrderStatusPending,
+		GroupID:      &group.ID,
+		GroupLeg:     domain.OrderLegStop,
+	}
+	if group.StopLimitPrice > 0 {
+		stop.Kind = domain.OrderKindStopLimit
+		stop.LimitPrice = group.StopLimitPrice
+	}
+	return stop
+}
+
+func oppositeSide(side string) string {
+	if side == domain.OrderSideBuy {
+		return domain.OrderSideSell
+	}
+	return domain.OrderSideBuy
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+	service "github.com/kannan112/mock-trading-platform-api/pkg/usecase/interfaces"
+)
+
+type portfolioUseCase struct {
+	portfolioRepo interfaces.PortfolioRepository
+	tradeRepo     interfaces.TradeRepository
+	accountRepo   interfaces.AccountRepository
+	marketData    marketdata.MarketDataProvider
+}
+
+func NewPortfolioUseCase(portfolioRepo interfaces.PortfolioRepository, tradeRepo interfaces.TradeRepository,
+	accountRepo interfaces.AccountRepository, marketData marketdata.MarketDataProvider) service.PortfolioUseCase {
+	return &portfolioUseCase{
+		portfolioRepo: portfolioRepo,
+		tradeRepo:     tradeRepo,
+		accountRepo:   accountRepo,
+		marketData:    marketData,
+	}
+}
+
+// range of the equity curve and the stats when the request leave it out
+const defaultPortfolioRange = 30 * 24 * time.Hour
+
+// range of the request, the default range up to now for what it leave out
+func timeRange(from, to time.Time) (time.Time, time.Time, error) {
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultPortfolioRange)
+	}
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from %s must be before to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return from, to, nil
+}
+
+// GetPositions list the open positions of the user with their unrealized PnL, marked at the bid
+// they would close at
+func (c *portfolioUseCase) GetPositions(ctx context.Context, uid uint) ([]response.Position, error) {
+
+	positions, err := c.tradeRepo.GetOpenPositions(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]response.Position, len(positions))
+	for i, position := range positions {
+
+		data[i] = response.Position{
+			Symbol:      position.Symbol,
+			Volume:      position.Volume,
+			EntryPrice:  position.EntryPrice,
+			RealizedPnl: position.RealizedPnl,
+		}
+
+		quote, err := c.marketData.GetQuote(ctx, position.Symbol)
+		if err != nil {
+			log.Printf("Failed to price the %s position of user %d: %v", position.Symbol, uid, err)
+			continue
+		}
+		price, err := marketPrice(quote, domain.OrderSideSell)
+		if err != nil {
+			log.Printf("Failed to price the %s position of user %d: %v", position.Symbol, uid, err)
+			continue
+		}
+
+		data[i].MarkPrice = price
+		data[i].MarketValue = position.Volume * price
+		data[i].UnrealizedPnl = position.Volume * (price - position.EntryPrice)
+		if cost := position.Volume * position.EntryPrice; cost > 0 {
+			data[i].UnrealizedPnlPercent = data[i].UnrealizedPnl / cost * 100
+		}
+		data[i].Priced = true
+	}
+
+	return data, nil
+}
+
+// SnapshotEquity value the accounts of every user at the current prices and save their equity,
+// each asset is quoted once for all the users
+func (c *portfolioUseCase) SnapshotEquity(ctx context.Context) error {
+
+	accounts, err := c.accountRepo.GetAllAccounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	pricer := newAssetPricer(c.marketData)
+	takenAt := time.Now()
+
+	var snapshots []domain.EquitySnapshot
+	for start := 0; start < len(accounts); {
+		end := start
+		for end < len(accounts) && accounts[end].UserID == accounts[start].UserID {
+			end++
+		}
+
+		snapshot := domain.EquitySnapshot{UserID: accounts[start].UserID, TakenAt: takenAt}
+		balances, equity := valueAccounts(ctx, pricer, accounts[start:end])
+		for _, balance := range balances {
+			if balance.Asset == marketdata.DefaultQuoteAsset {
+				snapshot.Cash += balance.Value
+			} else {
+				snapshot.PositionsValue += balance.Value
+			}
+		}
+		snapshot.Equity = equity
+
+		snapshots = append(snapshots, snapshot)
+		start = end
+	}
+
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return c.portfolioRepo.CreateEquitySnapshots(ctx, snapshots)
+}
+
+// GetEquityCurve return the equity snapshots of the user in the range, oldest first, keeping
+// the last snapshot of each interval from the start of the range when an interval is given
+func (c *portfolioUseCase) GetEquityCurve(ctx context.Context, uid uint, body request.EquityCurveRequest) ([]response.EquityPoint, error) {
+
+	from, to, err := timeRange(body.From, body.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var interval time.Duration
+	if body.Interval != "" {
+		if interval, err = time.ParseDuration(body.Interval); err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid interval %q, expected a duration like \"1h\" or \"24h\"", body.Interval)
+		}
+	}
+
+	snapshots, err := c.portfolioRepo.GetEquitySnapshots(ctx, uid, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := make([]response.EquityPoint, 0, len(snapshots))
+	lastBucket := int64(-1)
+	for _, snapshot := range snapshots {
+		point := response.EquityPoint{
+			Time:           snapshot.TakenAt,
+			Cash:           snapshot.Cash,
+			PositionsValue: snapshot.PositionsValue,
+			Equity:         snapshot.Equity,
+		}
+
+		if interval > 0 {
+			bucket := int64(snapshot.TakenAt.Sub(from) / interval)
+			if bucket == lastBucket {
+				curve[len(curve)-1] = point
+				continue
+			}
+			lastBucket = bucket
+		}
+		curve = append(curve, point)
+	}
+
+	return curve, nil
+}
+
+// GetStats compute the returns, risk ratios and drawdown of the equity snapshots of the window
+// and the trade stats of the trades which closed lots in it
+func (c *portfolioUseCase) GetStats(ctx context.Context, uid uint, body request.PortfolioStatsRequest) (response.PortfolioStats, error) {
+
+	from, to, err := timeRange(body.From, body.To)
+	if err != nil {
+		return response.PortfolioStats{}, err
+	}
+
+	snapshots, err := c.portfolioRepo.GetEquitySnapshots(ctx, uid, from, to)
+	if err != nil {
+		return response.PortfolioStats{}, err
+	}
+	trades, err := c.tradeRepo.GetClosingTrades(ctx, uid, from, to)
+	if err != nil {
+		return response.PortfolioStats{}, err
+	}
+
+	stats := response.PortfolioStats{From: from, To: to}
+	equityStats(&stats, snapshots, body.RiskFreeRate)
+	tradeStats(&stats, trades)
+
+	return stats, nil
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+)
+
+// volume left on a position or a lot below which it is closed
+const positionDust = 1e-12
+
+// save a fill of qty at price of the order as a trade with its fee, open a lot with a buy or close the lots
+// a sell reduce by the cost basis method of the user, then move the position by it
+func (c *orderUseCase) recordTrade(ctx context.Context, order domain.Order, qty, price float64,
+	liquidity string, feeRate float64) (domain.Trade, error) {
+
+	_, quote, err := c.symbolAssets(ctx, order.Symbol)
+	if err != nil {
+		return domain.Trade{}, err
+	}
+
+	trade := domain.Trade{
+		UserID:    order.UserID,
+		OrderID:   order.ID,
+		Symbol:    order.Symbol,
+		Volume:    qty,
+		Price:     price,
+		Type:      order.Type,
+		Liquidity: liquidity,
+		FeeRate:   feeRate,
+		Fee:       qty * price * feeRate,
+		FeeAsset:  quote,
+		Timestamp: time.Now(),
+	}
+
+	tid, err := c.tradeRepo.CreateTrade(ctx, trade)
+	if err != nil {
+		return domain.Trade{}, err
+	}
+	trade.ID = tid
+
+	position, err := c.tradeRepo.GetPositionForUpdate(ctx, trade.UserID, trade.Symbol)
+	if err != nil {
+		return domain.Trade{}, err
+	}
+	lots, err := c.openLots(ctx, position)
+	if err != nil {
+		return domain.Trade{}, err
+	}
+	method, err := c.userRepo.GetCostBasisMethod(ctx, trade.UserID)
+	if err != nil {
+		return domain.Trade{}, err
+	}
+
+	closed, matches, rest := matchLots(lots, trade, method)
+
+	for i := range closed {
+		if closed[i].Remaining != lots[i].Remaining {
+			if err := c.tradeRepo.UpdateLotRemaining(ctx, closed[i].ID, closed[i].Remaining); err != nil {
+				return domain.Trade{}, err
+			}
+		}
+	}
+	for _, match := range matches {
+		if err := c.tradeRepo.CreateLotMatch(ctx, match); err != nil {
+			return domain.Trade{}, err
+		}
+		trade.RealizedPnl += match.RealizedPnl
+	}
+	if len(matches) > 0 {
+		if err := c.tradeRepo.SetTradeRealizedPnl(ctx, trade.ID, trade.RealizedPnl); err != nil {
+			return domain.Trade{}, err
+		}
+	}
+
+	if trade.Type == domain.OrderSideBuy && rest > positionDust {
+		lot := domain.TaxLot{
+			UserID:    trade.UserID,
+			Symbol:    trade.Symbol,
+			TradeID:   &trade.ID,
+			Side:      trade.Type,
+			Volume:    rest,
+			Remaining: rest,
+			Price:     trade.Price,
+			OpenedAt:  trade.Timestamp,
+		}
+		if lot.ID, err = c.tradeRepo.CreateLot(ctx, lot); err != nil {
+			return domain.Trade{}, err
+		}
+		closed = append(closed, lot)
+	}
+
+	position.RealizedPnl += trade.RealizedPnl
+	if err := c.tradeRepo.SavePosition(ctx, positionOfLots(position, closed)); err != nil {
+		return domain.Trade{}, err
+	}
+
+	return trade, nil
+}
+
+// open lots of the position, a position opened before lots were tracked is carried over as one lot
+func (c *orderUseCase) openLots(ctx context.Context, position domain.Position) ([]domain.TaxLot, error) {
+
+	lots, err := c.tradeRepo.GetOpenLots(ctx, position.UserID, position.Symbol)
+	if err != nil || len(lots) > 0 || position.Volume < positionDust {
+		return lots, err
+	}
+
+	lot := domain.TaxLot{
+		UserID:    position.UserID,
+		Symbol:    position.Symbol,
+		Side:      domain.OrderSideBuy,
+		Volume:    position.Volume,
+		Remaining: position.Volume,
+		Price:     position.EntryPrice,
+		OpenedAt:  position.CreatedAt,
+	}
+
+	if lot.ID, err = c.tradeRepo.CreateLot(ctx, lot); err != nil {
+		return nil, err
+	}
+	return []domain.TaxLot{lot}, nil
+}
+
+// match a sell with the open lots by the method, returning the lots with what the sell left of them, the
+// matches and the volume of the trade the lots didn't cover. a buy match nothing and is all left to open a lot
+// with. sells reserve the base asset they sell so there's no short, what a sell leave over was held before the
+// lots were tracked and has no cost to realize against.
+// FIFO close the oldest lots first, LIFO the newest and AVERAGE every lot in proportion at their average price.
+func matchLots(lots []domain.TaxLot, trade domain.Trade, method string) ([]domain.TaxLot, []domain.LotMatch, float64) {
+
+	closed := append([]domain.TaxLot(nil), lots...)
+	if len(closed) == 0 || trade.Type == domain.OrderSideBuy {
+		return closed, nil, trade.Volume
+	}
+
+	var total, cost float64
+	for _, lot := range closed {
+		total += lot.Remaining
+		cost += lot.Remaining * lot.Price
+	}
+	qty := math.Min(trade.Volume, total)
+
+	order := make([]int, len(closed))
+	for i := range order {
+		order[i] = i
+		if method == domain.CostBasisLIFO {
+			order[i] = len(closed) - 1 - i
+		}
+	}
+
+	var matches []domain.LotMatch
+	left := qty
+	for _, i := range order {
+		lot := &closed[i]
+
+		volume, openPrice := math.Min(left, lot.Remaining), lot.Price
+		if method == domain.CostBasisAverage {
+			volume, openPrice = lot.Remaining*qty/total, cost/total
+		}
+		if volume <= 0 {
+			continue
+		}
+
+		lot.Remaining -= volume
+		if lot.Remaining < positionDust {
+			lot.Remaining = 0
+		}
+		left -= volume
+
+		matches = append(matches, domain.LotMatch{
+			UserID:      trade.UserID,
+			TradeID:     trade.ID,
+			LotID:       lot.ID,
+			Method:      method,
+			Volume:      volume,
+			OpenPrice:   openPrice,
+			ClosePrice:  trade.Price,
+			RealizedPnl: (trade.Price - openPrice) * volume,
+		})
+
+		if method != domain.CostBasisAverage && left <= 0 {
+			break
+		}
+	}
+
+	return closed, matches, trade.Volume - qty
+}
+
+// position of the open lots at their average price
+func positionOfLots(position domain.Position, lots []domain.TaxLot) domain.Position {
+
+	var volume, cost float64
+	for _, lot := range lots {
+		volume += lot.Remaining
+		cost += lot.Remaining * lot.Price
+	}
+
+	position.Volume, position.EntryPrice = 0, 0
+	if volume < positionDust {
+		return position
+	}
+
+	position.Volume = volume
+	position.EntryPrice = cost / volume
+	return position
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+)
+
+func TestMatchLots(t *testing.T) {
+
+	lots := []domain.TaxLot{
+		{ID: 1, Side: domain.OrderSideBuy, Volume: 1, Remaining: 1, Price: 100},
+		{ID: 2, Side: domain.OrderSideBuy, Volume: 2, Remaining: 1, Price: 130},
+	}
+
+	tests := []struct {
+		name      string
+		trade     domain.Trade
+		method    string
+		remaining []float64
+		pnl       float64
+		rest      float64
+	}{
+		{
+			name:      "buy open a lot",
+			trade:     domain.Trade{Type: domain.OrderSideBuy, Volume: 0.5, Price: 150},
+			method:    domain.CostBasisFIFO,
+			remaining: []float64{1, 1},
+			rest:      0.5,
+		},
+		{
+			name:      "sell closing the oldest lot first",
+			trade:     domain.Trade{Type: domain.OrderSideSell, Volume: 1.5, Price: 150},
+			method:    domain.CostBasisFIFO,
+			remaining: []float64{0, 0.5},
+			pnl:       50 + 10,
+		},
+		{
+			name:      "sell closing the newest lot first",
+			trade:     domain.Trade{Type: domain.OrderSideSell, Volume: 1.5, Price: 150},
+			method:    domain.CostBasisLIFO,
+			remaining: []float64{0.5, 0},
+			pnl:       20 + 25,
+		},
+		{
+			name:      "sell closing every lot at the average",
+			trade:     domain.Trade{Type: domain.OrderSideSell, Volume: 1, Price: 150},
+			method:    domain.CostBasisAverage,
+			remaining: []float64{0.5, 0.5},
+			pnl:       35,
+		},
+		{
+			name:      "sell of more than the lots hold",
+			trade:     domain.Trade{Type: domain.OrderSideSell, Volume: 3, Price: 150},
+			method:    domain.CostBasisFIFO,
+			remaining: []float64{0, 0},
+			pnl:       70,
+			rest:      1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			closed, matches, rest := matchLots(lots, tt.trade, tt.method)
+
+			for i, lot := range closed {
+				if math.Abs(lot.Remaining-tt.remaining[i]) > 1e-9 {
+					t.Errorf("lot %d: remaining %v, want %v", lot.ID, lot.Remaining, tt.remaining[i])
+				}
+			}
+			var pnl float64
+			for _, match := range matches {
+				pnl += match.RealizedPnl
+			}
+			if math.Abs(pnl-tt.pnl) > 1e-9 {
+				t.Errorf("realized %v, want %v", pnl, tt.pnl)
+			}
+			if math.Abs(rest-tt.rest) > 1e-9 {
+				t.Errorf("rest %v, want %v", rest, tt.rest)
+			}
+		})
+	}
+}
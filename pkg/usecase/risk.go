@@ -0,0 +1,360 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/request"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/notify"
+	service "github.com/kannan112/mock-trading-platform-api/pkg/usecase/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type riskUseCase struct {
+	riskRepo interfaces.RiskRepository
+	notifier notify.Notifier
+	defaults domain.RiskLimits
+}
+
+func NewRiskUseCase(cfg config.Config, riskRepo interfaces.RiskRepository, notifier notify.Notifier) service.RiskUseCase {
+	return &riskUseCase{
+		riskRepo: riskRepo,
+		notifier: notifier,
+		defaults: riskDefaults(cfg),
+	}
+}
+
+// GetRiskLimits return the limits the orders of the user are checked against
+func (c *riskUseCase) GetRiskLimits(ctx context.Context, uid uint) (response.RiskLimits, error) {
+
+	limits, custom, err := riskLimits(ctx, c.riskRepo, c.defaults, uid)
+	if err != nil {
+		return response.RiskLimits{}, err
+	}
+	return toRiskLimitsResponse(limits, custom), nil
+}
+
+// SetRiskLimits change the limits given in the body, the others keep the value the user has now,
+// the defaults when they never set their own
+func (c *riskUseCase) SetRiskLimits(ctx context.Context, uid uint, body request.RiskLimitsRequest) (response.RiskLimits, error) {
+
+	limits, _, err := riskLimits(ctx, c.riskRepo, c.defaults, uid)
+	if err != nil {
+		return response.RiskLimits{}, err
+	}
+
+	if body.MaxOrderNotional != nil {
+		limits.MaxOrderNotional = *body.MaxOrderNotional
+	}
+	if body.MaxPositionSize != nil {
+		limits.MaxPositionSize = *body.MaxPositionSize
+	}
+	if body.MaxOpenOrders != nil {
+		limits.MaxOpenOrders = *body.MaxOpenOrders
+	}
+	if body.MaxDailyVolume != nil {
+		limits.MaxDailyVolume = *body.MaxDailyVolume
+	}
+	if body.PriceBand != nil {
+		limits.PriceBand = *body.PriceBand
+	}
+	if body.MaxDailyLoss != nil {
+		limits.MaxDailyLoss = *body.MaxDailyLoss
+	}
+	if body.FlattenOnStopOut != nil {
+		limits.FlattenOnStopOut = *body.FlattenOnStopOut
+	}
+
+	limits.UserID = uid
+	if err := c.riskRepo.SaveRiskLimits(ctx, limits); err != nil {
+		return response.RiskLimits{}, err
+	}
+	return toRiskLimitsResponse(limits, true), nil
+}
+
+// ListStopOuts return the stop outs of the user, newest first
+func (c *riskUseCase) ListStopOuts(ctx context.Context, uid uint) ([]utils.StopOut, error) {
+	return c.riskRepo.GetStopOuts(ctx, uid)
+}
+
+// ResetStopOut lift the kill switch the user tripped today, they can trade again for the rest of the day
+func (c *riskUseCase) ResetStopOut(ctx context.Context, uid uint) error {
+
+	day := tradingDay(time.Now())
+
+	reset, err := c.riskRepo.ResetStopOut(ctx, uid, day)
+	if err != nil {
+		return err
+	}
+	if !reset {
+		return status.Errorf(codes.FailedPrecondition, "user %d is not stopped out", uid)
+	}
+
+	stopOut, err := c.riskRepo.GetStopOut(ctx, uid, day)
+	if err != nil {
+		return err
+	}
+	c.notifier.Publish(uid, notify.Event{Type: notify.EventStopOutReset, Data: toStopOutResponse(stopOut)})
+	return nil
+}
+
+// limits of the config for the users without their own
+func riskDefaults(cfg config.Config) domain.RiskLimits {
+	return domain.RiskLimits{
+		MaxOrderNotional: cfg.RiskMaxOrderNotional,
+		MaxPositionSize:  cfg.RiskMaxPositionSize,
+		MaxOpenOrders:    cfg.RiskMaxOpenOrders,
+		MaxDailyVolume:   cfg.RiskMaxDailyVolume,
+		PriceBand:        cfg.RiskPriceBand,
+		MaxDailyLoss:     cfg.RiskMaxDailyLoss,
+		FlattenOnStopOut: cfg.RiskFlattenOnStopOut,
+	}
+}
+
+// limits the user set, or the defaults and false when they set none
+func riskLimits(ctx context.Context, riskRepo interfaces.RiskRepository, defaults domain.RiskLimits,
+	uid uint) (domain.RiskLimits, bool, error) {
+
+	limits, err := riskRepo.GetRiskLimits(ctx, uid)
+	if err != nil {
+		return domain.RiskLimits{}, false, err
+	}
+	if limits.UserID == 0 {
+		return defaults, false, nil
+	}
+	return limits, true, nil
+}
+
+func toRiskLimitsResponse(limits domain.RiskLimits, custom bool) response.RiskLimits {
+	return response.RiskLimits{
+		MaxOrderNotional: limits.MaxOrderNotional,
+		MaxPositionSize:  limits.MaxPositionSize,
+		MaxOpenOrders:    limits.MaxOpenOrders,
+		MaxDailyVolume:   limits.MaxDailyVolume,
+		PriceBand:        limits.PriceBand,
+		MaxDailyLoss:     limits.MaxDailyLoss,
+		FlattenOnStopOut: limits.FlattenOnStopOut,
+		Custom:           custom,
+	}
+}
+
+// riskCheck is an order, or the legs of an order group, about to be placed or amended
+type riskCheck struct {
+	uid    uint
+	symbol string
+	side   string
+	// quantity of the order and the part of it left to fill, priced at the reserve price
+	volume    float64
+	remaining float64
+	price     float64
+	// limit prices the price band apply to, and the mark price of the symbol
+	limitPrices []float64
+	mark        float64
+	// orders the check add to the live orders of the user
+	newOrders int
+}
+
+// riskRule is one pre-trade check, it return the violation of the order against the limit of the rule or nil.
+// a rule whose limit is zero is not checked
+type riskRule struct {
+	name  string
+	limit func(limits domain.RiskLimits) float64
+	check func(ctx context.Context, c *orderUseCase, order riskCheck, limit float64) (*response.RiskViolation, error)
+}
+
+// the chain every order go through before it's saved
+var riskRules = []riskRule{
+	{
+		name:  domain.RiskRuleMaxOrderNotional,
+		limit: func(limits domain.RiskLimits) float64 { return limits.MaxOrderNotional },
+		check: checkOrderNotional,
+	},
+	{
+		name:  domain.RiskRuleMaxPositionSize,
+		limit: func(limits domain.RiskLimits) float64 { return limits.MaxPositionSize },
+		check: checkPositionSize,
+	},
+	{
+		name:  domain.RiskRuleMaxOpenOrders,
+		limit: func(limits domain.RiskLimits) float64 { return float64(limits.MaxOpenOrders) },
+		check: checkOpenOrders,
+	},
+	{
+		name:  domain.RiskRuleMaxDailyVolume,
+		limit: func(limits domain.RiskLimits) float64 { return limits.MaxDailyVolume },
+		check: checkDailyVolume,
+	},
+	{
+		name:  domain.RiskRulePriceBand,
+		limit: func(limits domain.RiskLimits) float64 { return limits.PriceBand },
+		check: checkPriceBand,
+	},
+}
+
+// run the order through every risk rule of the limits of the user, an order breaking any of them
+// is refused with the list of the rules it broke. no order get through a tripped kill switch.
+// called in the transaction saving the order, which hold the risk lock of the user until it end
+// so the orders placed at once don't all count the same open orders, volume and position
+func (c *orderUseCase) checkRisk(ctx context.Context, order riskCheck) error {
+
+	if err := c.riskRepo.LockUser(ctx, order.uid); err != nil {
+		return err
+	}
+
+	if err := c.checkStopOut(ctx, order.uid); err != nil {
+		return err
+	}
+
+	limits, _, err := riskLimits(ctx, c.riskRepo, c.riskDefaults, order.uid)
+	if err != nil {
+		return err
+	}
+
+	var violations []response.RiskViolation
+	for _, rule := range riskRules {
+		limit := rule.limit(limits)
+		if limit <= 0 {
+			continue
+		}
+
+		violation, err := rule.check(ctx, c, order, limit)
+		if err != nil {
+			return fmt.Errorf("failed to check %s risk rule: %w", rule.name, err)
+		}
+		if violation != nil {
+			violation.Rule = rule.name
+			violation.Limit = limit
+			violations = append(violations, *violation)
+		}
+	}
+
+	if len(violations) > 0 {
+		return &response.RiskError{Violations: violations}
+	}
+	return nil
+}
+
+// value of the order
+func checkOrderNotional(_ context.Context, _ *orderUseCase, order riskCheck, limit float64) (*response.RiskViolation, error) {
+
+	if value := order.volume * order.price; value > limit {
+		return &response.RiskViolation{
+			Message: fmt.Sprintf("order value %v is above the max order notional %v", value, limit),
+			Value:   value,
+		}, nil
+	}
+	return nil, nil
+}
+
+// value of the position in the symbol once a buy is filled, a sell only shrink it
+func checkPositionSize(ctx context.Context, c *orderUseCase, order riskCheck, limit float64) (*response.RiskViolation, error) {
+
+	if order.side != domain.OrderSideBuy {
+		return nil, nil
+	}
+
+	positions, err := c.tradeRepo.GetOpenPositions(ctx, order.uid)
+	if err != nil {
+		return nil, err
+	}
+	volume := order.remaining
+	for _, position := range positions {
+		if position.Symbol == order.symbol {
+			volume += position.Volume
+		}
+	}
+
+	if value := volume * order.price; value > limit {
+		return &response.RiskViolation{
+			Message: fmt.Sprintf("position value %v of %s is above the max position size %v", value, order.symbol, limit),
+			Value:   value,
+		}, nil
+	}
+	return nil, nil
+}
+
+// live orders of the user with the new ones
+func checkOpenOrders(ctx context.Context, c *orderUseCase, order riskCheck, limit float64) (*response.RiskViolation, error) {
+
+	if order.newOrders == 0 {
+		return nil, nil
+	}
+
+	count, err := c.orderRepo.CountLiveOrders(ctx, order.uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if open := float64(count + order.newOrders); open > limit {
+		return &response.RiskViolation{
+			Message: fmt.Sprintf("%v open orders are above the max open orders %v", open, limit),
+			Value:   open,
+		}, nil
+	}
+	return nil, nil
+}
+
+// value traded by the user since the start of the UTC day and the value the order has left to fill
+func checkDailyVolume(ctx context.Context, c *orderUseCase, order riskCheck, limit float64) (*response.RiskViolation, error) {
+
+	traded, err := c.tradeRepo.GetTradedValue(ctx, order.uid, tradingDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	if value := traded + order.remaining*order.price; value > limit {
+		return &response.RiskViolation{
+			Message: fmt.Sprintf("daily traded value %v is above the max daily volume %v", value, limit),
+			Value:   value,
+		}, nil
+	}
+	return nil, nil
+}
+
+// distance of the limit prices from the mark price, a fraction of the mark price
+func checkPriceBand(_ context.Context, _ *orderUseCase, order riskCheck, limit float64) (*response.RiskViolation, error) {
+
+	if order.mark <= 0 {
+		return nil, nil
+	}
+
+	var distance, farthest float64
+	for _, price := range order.limitPrices {
+		if price <= 0 {
+			continue
+		}
+		if d := math.Abs(price-order.mark) / order.mark; d > distance {
+			distance, farthest = d, price
+		}
+	}
+
+	if distance > limit {
+		return &response.RiskViolation{
+			Message: fmt.Sprintf("limit price %v is %.2f%% away from the mark price %v, above the price band of %.2f%%",
+				farthest, distance*100, order.mark, limit*100),
+			Value: distance,
+		}, nil
+	}
+	return nil, nil
+}
+
+// mid of the bid and ask of the quote, or the one side quoted
+func markPrice(quote marketdata.Quote) float64 {
+	switch {
+	case quote.BidPrice > 0 && quote.AskPrice > 0:
+		return (quote.BidPrice + quote.AskPrice) / 2
+	case quote.BidPrice > 0:
+		return quote.BidPrice
+	default:
+		return quote.AskPrice
+	}
+}
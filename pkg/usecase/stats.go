@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"math"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+const year = 365 * 24 * time.Hour
+
+// returns, ratios and drawdown of the equity snapshots, the period of the returns is the average
+// spacing of the snapshots and the ratios are annualized by it
+func equityStats(stats *response.PortfolioStats, snapshots []domain.EquitySnapshot, riskFreeRate float64) {
+
+	stats.Snapshots = len(snapshots)
+	if len(snapshots) == 0 {
+		return
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	stats.StartEquity, stats.EndEquity = first.Equity, last.Equity
+
+	stats.MaxDrawdown, stats.MaxDrawdownDurationHours = maxDrawdown(snapshots)
+
+	elapsed := last.TakenAt.Sub(first.TakenAt)
+	if len(snapshots) < 2 || first.Equity <= 0 || elapsed <= 0 {
+		return
+	}
+
+	total := last.Equity/first.Equity - 1
+	stats.TotalReturn = &total
+	annualized := math.Pow(1+total, float64(year)/float64(elapsed)) - 1
+	stats.AnnualizedReturn = &annualized
+
+	returns := make([]float64, 0, len(snapshots)-1)
+	for i := 1; i < len(snapshots); i++ {
+		if prev := snapshots[i-1].Equity; prev > 0 {
+			returns = append(returns, snapshots[i].Equity/prev-1)
+		}
+	}
+	if len(returns) < 2 {
+		return
+	}
+
+	periodsPerYear := float64(year) / (float64(elapsed) / float64(len(snapshots)-1))
+	riskFree := riskFreeRate / periodsPerYear
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance, downside float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+		if r < riskFree {
+			downside += (r - riskFree) * (r - riskFree)
+		}
+	}
+	stdDev := math.Sqrt(variance / float64(len(returns)-1))
+	downsideDev := math.Sqrt(downside / float64(len(returns)))
+
+	volatility := stdDev * math.Sqrt(periodsPerYear)
+	stats.Volatility = &volatility
+
+	if stdDev > 0 {
+		sharpe := (mean - riskFree) / stdDev * math.Sqrt(periodsPerYear)
+		stats.SharpeRatio = &sharpe
+	}
+	if downsideDev > 0 {
+		sortino := (mean - riskFree) / downsideDev * math.Sqrt(periodsPerYear)
+		stats.SortinoRatio = &sortino
+	}
+}
+
+// largest fall of the equity from a peak as a fraction of the peak, and the longest time it took to
+// get back to a peak, up to the last snapshot when it never did
+func maxDrawdown(snapshots []domain.EquitySnapshot) (float64, float64) {
+
+	var drawdown float64
+	var duration time.Duration
+
+	peak := snapshots[0]
+	for _, snapshot := range snapshots[1:] {
+		if snapshot.Equity >= peak.Equity {
+			peak = snapshot
+			continue
+		}
+		if peak.Equity > 0 {
+			drawdown = math.Max(drawdown, (peak.Equity-snapshot.Equity)/peak.Equity)
+		}
+		if below := snapshot.TakenAt.Sub(peak.TakenAt); below > duration {
+			duration = below
+		}
+	}
+
+	return drawdown, duration.Hours()
+}
+
+// win rate, average win and loss and profit factor of the trades which closed lots
+func tradeStats(stats *response.PortfolioStats, trades []utils.Trade) {
+
+	stats.ClosingTrades = len(trades)
+	if len(trades) == 0 {
+		return
+	}
+
+	var grossProfit, grossLoss float64
+	for _, trade := range trades {
+		stats.RealizedPnl += trade.RealizedPnl
+		switch {
+		case trade.RealizedPnl > 0:
+			stats.Wins++
+			grossProfit += trade.RealizedPnl
+		case trade.RealizedPnl < 0:
+			stats.Losses++
+			grossLoss -= trade.RealizedPnl
+		}
+	}
+
+	winRate := float64(stats.Wins) / float64(len(trades))
+	stats.WinRate = &winRate
+
+	if stats.Wins > 0 {
+		averageWin := grossProfit / float64(stats.Wins)
+		stats.AverageWin = &averageWin
+	}
+	if stats.Losses > 0 {
+		averageLoss := -grossLoss / float64(stats.Losses)
+		stats.AverageLoss = &averageLoss
+	}
+	if grossLoss > 0 {
+		profitFactor := grossProfit / grossLoss
+		stats.ProfitFactor = &profitFactor
+	}
+}
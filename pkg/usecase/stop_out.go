@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/notify"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+// trading day of t, the UTC day the daily limits count from
+func tradingDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// EnforceLossLimits stop out the users whose loss of the trading day reached their daily loss limit,
+// realized by the trades of the day and unrealized by the change of their equity since the start of the day.
+// a stop out cancel the live orders of the user, close their positions when they flatten on stop out and
+// block new orders for the rest of the day. a user is stopped out once a day, after an admin reset they
+// trade until the next day. run by the loss limit job
+func (c *orderUseCase) EnforceLossLimits(ctx context.Context) error {
+
+	day := tradingDay(time.Now())
+
+	users, err := c.tradeRepo.GetTradingUsers(ctx, day)
+	if err != nil {
+		return err
+	}
+
+	for _, uid := range users {
+		if err := c.enforceLossLimit(ctx, uid, day); err != nil {
+			log.Printf("Failed to check the daily loss of user %d: %v", uid, err)
+		}
+	}
+	return nil
+}
+
+func (c *orderUseCase) enforceLossLimit(ctx context.Context, uid uint, day time.Time) error {
+
+	limits, _, err := riskLimits(ctx, c.riskRepo, c.riskDefaults, uid)
+	if err != nil {
+		return err
+	}
+	if limits.MaxDailyLoss <= 0 {
+		return nil
+	}
+
+	stopOut, err := c.riskRepo.GetStopOut(ctx, uid, day)
+	if err != nil {
+		return err
+	}
+	if stopOut.ID != 0 {
+		return nil
+	}
+
+	realized, unrealized, err := c.dailyPnl(ctx, uid, day)
+	if err != nil {
+		return err
+	}
+	loss := -(realized + unrealized)
+	if loss < limits.MaxDailyLoss {
+		return nil
+	}
+
+	return c.stopOut(ctx, domain.StopOut{
+		UserID:        uid,
+		TradingDay:    day,
+		Loss:          loss,
+		LossLimit:     limits.MaxDailyLoss,
+		RealizedPnl:   realized,
+		UnrealizedPnl: unrealized,
+		Flatten:       limits.FlattenOnStopOut,
+	})
+}
+
+// realized PnL of the trades of the user since the start of the day, and unrealized PnL of the day, the change of
+// their equity since its first snapshot of the day that no trade realized. the equity is marked the way the snapshots
+// are, so a position carried from an earlier day only count what it lost today. until the day has a snapshot
+// only the realized PnL count
+func (c *orderUseCase) dailyPnl(ctx context.Context, uid uint, day time.Time) (float64, float64, error) {
+
+	realized, err := c.tradeRepo.GetRealizedPnl(ctx, uid, day)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	start, err := c.portfolioRepo.GetFirstEquitySnapshot(ctx, uid, day)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start.ID == 0 {
+		return realized, 0, nil
+	}
+
+	accounts, err := c.accountRepo.GetAccounts(ctx, uid)
+	if err != nil {
+		return 0, 0, err
+	}
+	balances, equity := valueAccounts(ctx, newAssetPricer(c.marketData), accounts)
+	for _, balance := range balances {
+		// an unpriced balance would look like a loss
+		if !balance.Priced {
+			return 0, 0, fmt.Errorf("failed to value the %s balance of user %d", balance.Asset, uid)
+		}
+	}
+
+	// the trades realized before the snapshot are in its equity already
+	realizedSince, err := c.tradeRepo.GetRealizedPnl(ctx, uid, start.TakenAt)
+	if err != nil {
+		return 0, 0, err
+	}
+	return realized, equity - start.Equity - realizedSince, nil
+}
+
+// trip the kill switch of the user, it's saved first so no new order get through while the live orders
+// are cancelled and the positions closed. the user is told once it's done
+func (c *orderUseCase) stopOut(ctx context.Context, stopOut domain.StopOut) error {
+
+	id, err := c.riskRepo.CreateStopOut(ctx, stopOut)
+	if err != nil {
+		return err
+	}
+	stopOut.ID = id
+	stopOut.CreatedAt = time.Now()
+
+	log.Printf("User %d stopped out, daily loss %v reached the limit %v", stopOut.UserID, stopOut.Loss, stopOut.LossLimit)
+
+	cancelled := c.cancelLiveOrders(ctx, stopOut.UserID)
+
+	closed := 0
+	if stopOut.Flatten {
+		positions, err := c.tradeRepo.GetOpenPositions(ctx, stopOut.UserID)
+		if err != nil {
+			return err
+		}
+		for _, position := range positions {
+			if err := c.closePosition(ctx, position); err != nil {
+				log.Printf("Failed to close the %s position of user %d: %v", position.Symbol, stopOut.UserID, err)
+				continue
+			}
+			closed++
+		}
+	}
+
+	log.Printf("Stop out of user %d cancelled %d orders and closed %d positions", stopOut.UserID, cancelled, closed)
+
+	c.notifier.Publish(stopOut.UserID, notify.Event{Type: notify.EventStopOut, Data: toStopOutResponse(stopOut)})
+	return nil
+}
+
+// cancel every live order of the user, the groups as a whole, and return how many were cancelled
+func (c *orderUseCase) cancelLiveOrders(ctx context.Context, uid uint) int {
+
+	orders, err := c.orderRepo.GetLiveOrders(ctx, uid)
+	if err != nil {
+		log.Printf("Failed to fetch the live orders of user %d: %v", uid, err)
+		return 0
+	}
+
+	cancelled := 0
+	groups := make(map[uint]bool)
+	for i := range orders {
+		order := &orders[i]
+
+		if order.GroupID != nil {
+			if groups[*order.GroupID] {
+				continue
+			}
+			groups[*order.GroupID] = true
+
+			ok, err := c.cancelOrderGroup(ctx, *order.GroupID, uid)
+			if err != nil {
+				log.Printf("Failed to cancel order group %d: %v", *order.GroupID, err)
+			}
+			if ok {
+				cancelled++
+				continue
+			}
+		}
+
+		if err := c.cancelOrder(ctx, order, domain.OrderEndStopOut); err != nil {
+			log.Printf("Failed to cancel order %d: %v", order.ID, err)
+			continue
+		}
+		cancelled++
+	}
+	return cancelled
+}
+
+// close the position with a market order, the volume is rounded down to the step size of the symbol
+// and the dust below it is left
+func (c *orderUseCase) closePosition(ctx context.Context, position domain.Position) error {
+
+	symbol, err := c.symbolRepo.GetSymbol(ctx, position.Symbol)
+	if err != nil {
+		return err
+	}
+	volume, err := filterVolume(symbol, position.Volume)
+	if err != nil {
+		return err
+	}
+
+	book, err := c.orderBook(ctx, position.Symbol)
+	if err != nil {
+		return err
+	}
+
+	order := domain.Order{
+		OrderUUID:   uuid.New().String(),
+		UserID:      position.UserID,
+		Symbol:      position.Symbol,
+		Volume:      volume,
+		Type:        domain.OrderSideSell,
+		Kind:        domain.OrderKindMarket,
+		Status:      domain.OrderStatusOpen,
+		TimeInForce: domain.TimeInForceGTC,
+	}
+
+	price, err := reservePrice(order, book)
+	if err != nil {
+		return err
+	}
+
+	return c.orderRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := c.createReservedOrder(ctx, &order, c.reservation(order, price)); err != nil {
+			return err
+		}
+		return c.executeOrder(ctx, &order, book, domain.LiquidityTaker)
+	})
+}
+
+// refuse the orders of a user whose kill switch is tripped
+func (c *orderUseCase) checkStopOut(ctx context.Context, uid uint) error {
+
+	stopOut, err := c.riskRepo.GetStopOut(ctx, uid, tradingDay(time.Now()))
+	if err != nil {
+		return err
+	}
+	if stopOut.ID == 0 || stopOut.ResetAt != nil {
+		return nil
+	}
+
+	return &response.RiskError{Violations: []response.RiskViolation{{
+		Rule: domain.RiskRuleDailyLoss,
+		Message: fmt.Sprintf("trading is halted until the next trading day, the daily loss %v reached the limit %v",
+			stopOut.Loss, stopOut.LossLimit),
+		Limit: stopOut.LossLimit,
+		Value: stopOut.Loss,
+	}}}
+}
+
+func toStopOutResponse(stopOut domain.StopOut) utils.StopOut {
+	return utils.StopOut{
+		ID:            stopOut.ID,
+		UserID:        stopOut.UserID,
+		TradingDay:    stopOut.TradingDay,
+		Loss:          stopOut.Loss,
+		LossLimit:     stopOut.LossLimit,
+		RealizedPnl:   stopOut.RealizedPnl,
+		UnrealizedPnl: stopOut.UnrealizedPnl,
+		Flatten:       stopOut.Flatten,
+		ResetAt:       stopOut.ResetAt,
+		CreatedAt:     stopOut.CreatedAt,
+	}
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+	service "github.com/kannan112/mock-trading-platform-api/pkg/usecase/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+type symbolUseCase struct {
+	symbolRepo interfaces.SymbolRepository
+	marketData marketdata.MarketDataProvider
+	seedFile   string
+}
+
+func NewSymbolUseCase(cfg config.Config, symbolRepo interfaces.SymbolRepository,
+	marketData marketdata.MarketDataProvider) service.SymbolUseCase {
+	return &symbolUseCase{
+		symbolRepo: symbolRepo,
+		marketData: marketData,
+		seedFile:   cfg.SymbolSeedFile,
+	}
+}
+
+func (c *symbolUseCase) ListSymbols(ctx context.Context) ([]utils.Symbol, error) {
+	return c.symbolRepo.GetSymbols(ctx)
+}
+
+// LoadSymbols save the symbols of the seed file, or of the exchange info of the feed without one,
+// into the registry. run by the symbol refresh job
+func (c *symbolUseCase) LoadSymbols(ctx context.Context) error {
+
+	var (
+		infos []marketdata.SymbolInfo
+		err   error
+	)
+	if c.seedFile != "" {
+		infos, err = marketdata.LoadExchangeInfo(c.seedFile)
+	} else {
+		infos, err = c.marketData.ExchangeInfo(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch exchange info: %w", err)
+	}
+
+	symbols := make([]domain.Symbol, 0, len(infos))
+	for _, info := range infos {
+		symbol, err := toSymbol(info)
+		if err != nil {
+			return err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	if err := c.symbolRepo.SaveSymbols(ctx, symbols); err != nil {
+		return err
+	}
+
+	log.Printf("Loaded %d symbols into the symbol registry", len(symbols))
+	return nil
+}
+
+// symbol of the registry for the exchange info of a symbol and its price, lot size and notional filters
+func toSymbol(info marketdata.SymbolInfo) (domain.Symbol, error) {
+
+	symbol := domain.Symbol{
+		Symbol:     marketdata.NormalizeSymbol(info.Symbol),
+		Status:     info.Status,
+		BaseAsset:  info.BaseAsset,
+		QuoteAsset: info.QuoteAsset,
+	}
+
+	for _, filter := range info.Filters {
+		var err error
+		switch filter.FilterType {
+		case marketdata.FilterPrice:
+			symbol.TickSize, err = parseFilterValue(filter.TickSize)
+		case marketdata.FilterLotSize:
+			if symbol.StepSize, err = parseFilterValue(filter.StepSize); err != nil {
+				break
+			}
+			if symbol.MinQty, err = parseFilterValue(filter.MinQty); err != nil {
+				break
+			}
+			symbol.MaxQty, err = parseFilterValue(filter.MaxQty)
+		case marketdata.FilterNotional, marketdata.FilterMinNotional:
+			symbol.MinNotional, err = parseFilterValue(filter.MinNotional)
+		}
+		if err != nil {
+			return domain.Symbol{}, fmt.Errorf("invalid %s filter of symbol %s: %w", filter.FilterType, info.Symbol, err)
+		}
+	}
+	return symbol, nil
+}
+
+// value of a filter field, zero when it's not set
+func parseFilterValue(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// symbol of the registry the user meant, the symbol itself or the asset quoted in the default quote asset.
+// only trading symbols are returned
+func resolveSymbol(ctx context.Context, symbolRepo interfaces.SymbolRepository, name string) (domain.Symbol, error) {
+
+	name = marketdata.NormalizeSymbol(name)
+
+	symbol, err := symbolRepo.GetSymbol(ctx, name)
+	if err != nil {
+		return domain.Symbol{}, err
+	}
+	if symbol.Symbol == "" && !strings.HasSuffix(name, marketdata.DefaultQuoteAsset) {
+		if symbol, err = symbolRepo.GetSymbol(ctx, name+marketdata.DefaultQuoteAsset); err != nil {
+			return domain.Symbol{}, err
+		}
+	}
+
+	if symbol.Symbol == "" {
+		return domain.Symbol{}, fmt.Errorf("unknown symbol %s", name)
+	}
+	if symbol.Status != marketdata.SymbolStatusTrading {
+		return domain.Symbol{}, fmt.Errorf("symbol %s is not trading, status: %s", symbol.Symbol, symbol.Status)
+	}
+	return symbol, nil
+}
+
+// quantities within this share of a step of the next step are rounded up to it, the volume divided
+// by the step may fall just short of a whole number of steps it is (0.3/0.1 is 2.9999999999999996)
+const stepTolerance = 1e-9
+
+// volume rounded down to the step size of the symbol and checked against its min and max quantity
+func filterVolume(symbol domain.Symbol, volume float64) (float64, error) {
+
+	if symbol.StepSize > 0 {
+		volume = roundToStep(math.Floor(volume/symbol.StepSize+stepTolerance), symbol.StepSize)
+	}
+
+	if volume <= 0 || volume < symbol.MinQty {
+		return 0, fmt.Errorf("volume %v is below the minimum quantity %v of %s", volume, symbol.MinQty, symbol.Symbol)
+	}
+	if symbol.MaxQty > 0 && volume > symbol.MaxQty {
+		return 0, fmt.Errorf("volume %v is above the maximum quantity %v of %s", volume, symbol.MaxQty, symbol.Symbol)
+	}
+	return volume, nil
+}
+
+// price rounded to the nearest tick of the symbol
+func filterPrice(symbol domain.Symbol, price float64) float64 {
+
+	if symbol.TickSize <= 0 || price <= 0 {
+		return price
+	}
+	return roundToStep(math.Round(price/symbol.TickSize), symbol.TickSize)
+}
+
+// check the value of volume at price against the min notional of the symbol
+func checkNotional(symbol domain.Symbol, volume, price float64) error {
+
+	if notional := volume * price; notional < symbol.MinNotional {
+		return fmt.Errorf("order value %v is below the minimum notional %v of %s", notional, symbol.MinNotional, symbol.Symbol)
+	}
+	return nil
+}
+
+// steps times step, without the float error of the multiplication past the decimals of the step
+func roundToStep(steps, step float64) float64 {
+
+	decimals := 0
+	if _, fraction, found := strings.Cut(strconv.FormatFloat(step, 'f', -1, 64), "."); found {
+		decimals = len(fraction)
+	}
+
+	value, _ := strconv.ParseFloat(strconv.FormatFloat(steps*step, 'f', decimals, 64), 64)
+	return value
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// order kinds waiting for a trigger price
+func isTriggerKind(kind string) bool {
+	return kind == domain.OrderKindStopMarket || kind == domain.OrderKindStopLimit || kind == domain.OrderKindTakeProfit ||
+		kind == domain.OrderKindTrailingStop
+}
+
+// a stop trigger once the price move against the position it protect, a buy stop when the ask
+// rise to the trigger and a sell stop when the bid fall to it, a take profit on the opposite move
+func isTriggered(order domain.Order, quote marketdata.Quote) bool {
+
+	price, err := marketPrice(quote, order.Type)
+	if err != nil {
+		return false
+	}
+
+	rising := order.Type == domain.OrderSideBuy
+	if order.Kind == domain.OrderKindTakeProfit {
+		rising = !rising
+	}
+
+	if rising {
+		return price >= order.TriggerPrice
+	}
+	return price <= order.TriggerPrice
+}
+
+// place the child order of a pending order once the quote reach its trigger, a market child is
+// filled right away across the book and a limit child is returned open for matching. nil when nothing
+// triggered. the funds reserved by the order move to its child.
+func (c *orderUseCase) triggerOrder(ctx context.Context, order domain.Order, book *bookLiquidity) (*domain.Order, error) {
+
+	quote := book.quote
+
+	if order.Kind == domain.OrderKindTrailingStop {
+		if err := c.trailOrder(ctx, &order, quote); err != nil {
+			return nil, err
+		}
+	}
+
+	if !isTriggered(order, quote) {
+		return nil, nil
+	}
+
+	var child *domain.Order
+	err := c.orderRepo.Transaction(ctx, func(ctx context.Context) error {
+		claimed, err := c.claimGroupLeg(ctx, &order)
+		if err != nil || !claimed {
+			return err
+		}
+
+		now := time.Now()
+		triggered, err := c.orderRepo.TriggerOrder(ctx, order.ID, now)
+		if err != nil || !triggered {
+			return err
+		}
+
+		placed := domain.Order{
+			OrderUUID:     uuid.New().String(),
+			UserID:        order.UserID,
+			Symbol:        order.Symbol,
+			Volume:        order.Volume,
+			Type:          order.Type,
+			Kind:          domain.OrderKindMarket,
+			Reserved:      order.Reserved,
+			Status:        domain.OrderStatusOpen,
+			TimeInForce:   order.TimeInForce,
+			ExpiresAt:     order.ExpiresAt,
+			ParentOrderID: &order.ID,
+			CreatedAt:     now,
+		}
+		if order.Kind == domain.OrderKindStopLimit {
+			placed.Kind = domain.OrderKindLimit
+			placed.LimitPrice = order.LimitPrice
+		}
+
+		if err := c.createOrder(ctx, &placed); err != nil {
+			return fmt.Errorf("failed to place the order triggered by order %d: %w", order.ID, err)
+		}
+
+		if placed.Kind == domain.OrderKindMarket {
+			err := c.executeOrder(ctx, &placed, book, domain.LiquidityTaker)
+			// a child the book can't fill without partial fills end unfilled, the order stay triggered
+			if status.Code(err) == codes.FailedPrecondition {
+				err = c.cancelOrder(ctx, &placed, domain.OrderEndLiquidityExhausted)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		child = &placed
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return child, nil
+}
+
+// trigger of a trailing stop for the best price, below it for a sell and above it for a buy
+func trailingTrigger(order domain.Order, bestPrice float64) float64 {
+
+	offset := order.TrailingOffset
+	if order.TrailingPercent > 0 {
+		offset = bestPrice * order.TrailingPercent / 100
+	}
+
+	if order.Type == domain.OrderSideSell {
+		return bestPrice - offset
+	}
+	return bestPrice + offset
+}
+
+// move the trailing stop with the quote when it beat the best price, the highest bid for a sell
+// and the lowest ask for a buy, so the trigger only ever move in favour of the order
+func (c *orderUseCase) trailOrder(ctx context.Context, order *domain.Order, quote marketdata.Quote) error {
+
+	price, err := marketPrice(quote, order.Type)
+	if err != nil {
+		return nil
+	}
+	if order.Type == domain.OrderSideSell && price <= order.BestPrice ||
+		order.Type == domain.OrderSideBuy && price >= order.BestPrice {
+		return nil
+	}
+
+	// the trigger stay on the tick size of the symbol as when it was placed
+	symbol, err := c.symbolRepo.GetSymbol(ctx, order.Symbol)
+	if err != nil {
+		return err
+	}
+
+	trigger := filterPrice(symbol, trailingTrigger(*order, price))
+	if err := c.orderRepo.UpdateTrailingStop(ctx, order.ID, price, trigger); err != nil {
+		return err
+	}
+
+	order.BestPrice = price
+	order.TriggerPrice = trigger
+	return nil
+}
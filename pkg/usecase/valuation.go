@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/domain"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+)
+
+// assetPricer price assets in the quote asset at the bid of their USDT market, what selling them
+// would get right now. each asset is quoted once by a pricer.
+type assetPricer struct {
+	marketData marketdata.MarketDataProvider
+	prices     map[string]float64
+	errs       map[string]error
+}
+
+func newAssetPricer(marketData marketdata.MarketDataProvider) *assetPricer {
+	return &assetPricer{
+		marketData: marketData,
+		prices:     make(map[string]float64),
+		errs:       make(map[string]error),
+	}
+}
+
+func (p *assetPricer) price(ctx context.Context, asset string) (float64, error) {
+
+	if asset == marketdata.DefaultQuoteAsset {
+		return 1, nil
+	}
+	if price, ok := p.prices[asset]; ok {
+		return price, nil
+	}
+	if err, ok := p.errs[asset]; ok {
+		return 0, err
+	}
+
+	price, err := p.quote(ctx, asset)
+	if err != nil {
+		p.errs[asset] = err
+		return 0, err
+	}
+	p.prices[asset] = price
+	return price, nil
+}
+
+func (p *assetPricer) quote(ctx context.Context, asset string) (float64, error) {
+
+	quote, err := p.marketData.GetQuote(ctx, asset+marketdata.DefaultQuoteAsset)
+	if err != nil {
+		return 0, err
+	}
+	return marketPrice(quote, domain.OrderSideSell)
+}
+
+// value the balances of the accounts, an asset without a quote is listed unpriced and left out of the total
+func valueAccounts(ctx context.Context, pricer *assetPricer, accounts []domain.Account) ([]response.WalletBalance, float64) {
+
+	balances := make([]response.WalletBalance, 0, len(accounts))
+	total := 0.0
+
+	for _, account := range accounts {
+		balance := response.WalletBalance{
+			Asset:  account.Asset,
+			Free:   account.Balance,
+			Locked: account.Locked,
+			Total:  account.Balance + account.Locked,
+		}
+		if balance.Total <= 0 {
+			continue
+		}
+
+		price, err := pricer.price(ctx, account.Asset)
+		if err != nil {
+			log.Printf("Failed to price %s for the wallet of user %d: %v", account.Asset, account.UserID, err)
+		} else {
+			balance.Price = price
+			balance.Value = balance.Total * price
+			balance.Priced = true
+			total += balance.Value
+		}
+
+		balances = append(balances, balance)
+	}
+
+	return balances, total
+}
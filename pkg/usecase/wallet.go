@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/api/handler/response"
+	"github.com/kannan112/mock-trading-platform-api/pkg/repository/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/service/marketdata"
+	service "github.com/kannan112/mock-trading-platform-api/pkg/usecase/interfaces"
+	"github.com/kannan112/mock-trading-platform-api/pkg/utils"
+)
+
+type walletUseCase struct {
+	accountRepo interfaces.AccountRepository
+	userRepo    interfaces.UserRepository
+	marketData  marketdata.MarketDataProvider
+}
+
+func NewWalletUseCase(accountRepo interfaces.AccountRepository, userRepo interfaces.UserRepository,
+	marketData marketdata.MarketDataProvider) service.WalletUseCase {
+	return &walletUseCase{
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		marketData:  marketData,
+	}
+}
+
+// GetWallet list the assets the user hold, free and locked by live orders, valued at the bid of
+// their USDT market. an asset without a quote is listed unpriced instead of failing the wallet.
+func (c *walletUseCase) GetWallet(ctx context.Context, uid uint) (response.Wallet, error) {
+
+	accounts, err := c.accountRepo.GetAccounts(ctx, uid)
+	if err != nil {
+		return response.Wallet{}, err
+	}
+
+	method, err := c.userRepo.GetCostBasisMethod(ctx, uid)
+	if err != nil {
+		return response.Wallet{}, err
+	}
+
+	wallet := response.Wallet{
+		ValueAsset:      marketdata.DefaultQuoteAsset,
+		CostBasisMethod: method,
+	}
+	wallet.Assets, wallet.TotalValue = valueAccounts(ctx, newAssetPricer(c.marketData), accounts)
+
+	return wallet, nil
+}
+
+// GetLedger list the ledger entries of the available and locked balances of the user, newest first,
+// of one asset when given
+func (c *walletUseCase) GetLedger(ctx context.Context, uid uint, asset string) ([]utils.LedgerEntry, error) {
+	return c.accountRepo.GetLedgerEntries(ctx, uid, strings.ToUpper(asset))
+}
+
+// SetCostBasisMethod change how the next trades of the user close the lots of their positions,
+// the lots closed so far keep their matches
+func (c *walletUseCase) SetCostBasisMethod(ctx context.Context, uid uint, method string) error {
+	return c.userRepo.SetCostBasisMethod(ctx, uid, method)
+}
+
+// ReconcileLedger compare the balances of every account with the sum of its ledger entries and
+// check every journal balance, the drift found is logged for the auditors
+func (c *walletUseCase) ReconcileLedger(ctx context.Context) error {
+
+	drifts, err := c.accountRepo.GetAccountDrifts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, drift := range drifts {
+		log.Printf("Ledger drift on %s account of user %d: balance %v (ledger %v, drift %v), locked %v (ledger %v, drift %v)",
+			drift.Asset, drift.UserID,
+			drift.Balance, drift.LedgerBalance, drift.Balance-drift.LedgerBalance,
+			drift.Locked, drift.LedgerLocked, drift.Locked-drift.LedgerLocked)
+	}
+
+	journals, err := c.accountRepo.GetUnbalancedJournals(ctx)
+	if err != nil {
+		return err
+	}
+	for _, journal := range journals {
+		log.Printf("Unbalanced ledger journal %s: %v %s debited, %v %s credited",
+			journal.JournalID, journal.Debit, journal.Asset, journal.Credit, journal.Asset)
+	}
+
+	if len(drifts) > 0 || len(journals) > 0 {
+		log.Printf("Ledger reconciliation found %d drifted accounts and %d unbalanced journals", len(drifts), len(journals))
+	}
+	return nil
+}
@@ -3,23 +3,176 @@ package utils
 import "time"
 
 type Order struct {
-	ID        uint      `gorm:"column:id"`
-	OrderUUID string    `gorm:"column:orderUUID"`
-	Symbol    string    `gorm:"column:symbol"`
-	Volume    float32   `gorm:"column:volume"`
-	Price     float64   `gorm:"column:price"`
-	Type      string    `gorm:"column:type"`
-	Status    string    `gorm:"column:status"`
-	CreatedAt time.Time `gorm:"column:created_at"`
+	ID              uint       `gorm:"column:id"`
+	OrderUUID       string     `gorm:"column:order_uuid"`
+	Symbol          string     `gorm:"column:symbol"`
+	Volume          float64    `gorm:"column:volume"`
+	Price           float64    `gorm:"column:price"`
+	Type            string     `gorm:"column:type"`
+	Kind            string     `gorm:"column:kind"`
+	LimitPrice      float64    `gorm:"column:limit_price"`
+	TriggerPrice    float64    `gorm:"column:trigger_price"`
+	TrailingOffset  float64    `gorm:"column:trailing_offset"`
+	TrailingPercent float64    `gorm:"column:trailing_percent"`
+	BestPrice       float64    `gorm:"column:best_price"`
+	FilledVolume    float64    `gorm:"column:filled_volume"`
+	Reserved        float64    `gorm:"column:reserved"`
+	Fee             float64    `gorm:"column:fee"`
+	Status          string     `gorm:"column:status"`
+	TimeInForce     string     `gorm:"column:time_in_force"`
+	ExpiresAt       *time.Time `gorm:"column:expires_at"`
+	EndReason       string     `gorm:"column:end_reason"`
+	ParentID        *uint      `gorm:"column:parent_order_id"`
+	GroupID         *uint      `gorm:"column:group_id"`
+	GroupLeg        string     `gorm:"column:group_leg"`
+	TriggeredAt     *time.Time `gorm:"column:triggered_at"`
+	FilledAt        *time.Time `gorm:"column:filled_at"`
+	CancelledAt     *time.Time `gorm:"column:cancelled_at"`
+	CreatedAt       time.Time  `gorm:"column:created_at"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at"`
+	// orders placed by this one when triggered
+	Children []Order `gorm:"-"`
+	// changes of the order, oldest first
+	Amendments []OrderAmendment `gorm:"-"`
+}
+
+type OrderAmendment struct {
+	ID              uint      `json:"id" gorm:"column:id"`
+	OldVolume       float64   `json:"oldVolume" gorm:"column:old_volume"`
+	NewVolume       float64   `json:"newVolume" gorm:"column:new_volume"`
+	OldLimitPrice   float64   `json:"oldLimitPrice,omitempty" gorm:"column:old_limit_price"`
+	NewLimitPrice   float64   `json:"newLimitPrice,omitempty" gorm:"column:new_limit_price"`
+	OldTriggerPrice float64   `json:"oldTriggerPrice,omitempty" gorm:"column:old_trigger_price"`
+	NewTriggerPrice float64   `json:"newTriggerPrice,omitempty" gorm:"column:new_trigger_price"`
+	CreatedAt       time.Time `json:"createdAt" gorm:"column:created_at"`
 }
 
 type OrderResponse struct {
-	OrderID   uint      `json:"orderId"`
-	OrderUUID string    `json:"orderUUID"`
-	Symbol    string    `json:"symbol"`
-	Volume    float32   `json:"volume"`
-	Price     float64   `json:"price"`
-	Type      string    `json:"type"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"createdAt"`
+	OrderID         uint       `json:"orderId"`
+	OrderUUID       string     `json:"orderUUID"`
+	Symbol          string     `json:"symbol"`
+	Volume          float64    `json:"volume"`
+	Price           float64    `json:"price"`
+	Type            string     `json:"type"`
+	Kind            string     `json:"kind"`
+	LimitPrice      float64    `json:"limitPrice,omitempty"`
+	TriggerPrice    float64    `json:"triggerPrice,omitempty"`
+	TrailingOffset  float64    `json:"trailingOffset,omitempty"`
+	TrailingPercent float64    `json:"trailingPercent,omitempty"`
+	BestPrice       float64    `json:"bestPrice,omitempty"`
+	FilledVolume    float64    `json:"filledVolume"`
+	Reserved        float64    `json:"reserved,omitempty"`
+	Fee             float64    `json:"fee"`
+	Status          string     `json:"status"`
+	TimeInForce     string     `json:"timeInForce"`
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	EndReason       string     `json:"endReason,omitempty"`
+	ParentID        *uint      `json:"parentOrderId,omitempty"`
+	GroupID         *uint      `json:"groupId,omitempty"`
+	GroupLeg        string     `json:"groupLeg,omitempty"`
+	TriggeredAt     *time.Time `json:"triggeredAt,omitempty"`
+	FilledAt        *time.Time `json:"filledAt,omitempty"`
+	CancelledAt     *time.Time `json:"cancelledAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	// fills of the order, in the trade history
+	Trades []Trade `json:"trades,omitempty"`
+}
+
+type Trade struct {
+	ID          uint      `json:"id" gorm:"column:id"`
+	OrderID     uint      `json:"-" gorm:"column:order_id"`
+	Volume      float64   `json:"volume" gorm:"column:volume"`
+	Price       float64   `json:"price" gorm:"column:price"`
+	RealizedPnl float64   `json:"realizedPnl" gorm:"column:realized_pnl"`
+	Liquidity   string    `json:"liquidity" gorm:"column:liquidity"`
+	FeeRate     float64   `json:"feeRate" gorm:"column:fee_rate"`
+	Fee         float64   `json:"fee" gorm:"column:fee"`
+	FeeAsset    string    `json:"feeAsset" gorm:"column:fee_asset"`
+	Timestamp   time.Time `json:"timestamp" gorm:"column:timestamp"`
+	// lots the trade closed
+	Lots []LotMatch `json:"lots,omitempty" gorm:"-"`
+}
+
+type LotMatch struct {
+	LotID       uint      `json:"lotId" gorm:"column:lot_id"`
+	TradeID     uint      `json:"-" gorm:"column:trade_id"`
+	Method      string    `json:"method" gorm:"column:method"`
+	Volume      float64   `json:"volume" gorm:"column:volume"`
+	OpenPrice   float64   `json:"openPrice" gorm:"column:open_price"`
+	ClosePrice  float64   `json:"closePrice" gorm:"column:close_price"`
+	RealizedPnl float64   `json:"realizedPnl" gorm:"column:realized_pnl"`
+	OpenedAt    time.Time `json:"openedAt" gorm:"column:opened_at"`
+}
+
+type OrderGroupResponse struct {
+	GroupID         uint            `json:"groupId"`
+	GroupUUID       string          `json:"groupUUID"`
+	Type            string          `json:"type"`
+	Symbol          string          `json:"symbol"`
+	Side            string          `json:"side"`
+	Volume          float64         `json:"volume"`
+	TakeProfitPrice float64         `json:"takeProfitPrice"`
+	StopPrice       float64         `json:"stopPrice"`
+	StopLimitPrice  float64         `json:"stopLimitPrice,omitempty"`
+	Status          string          `json:"status"`
+	FilledOrderID   *uint           `json:"filledOrderId,omitempty"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	Orders          []OrderResponse `json:"orders"`
+}
+
+type LedgerEntry struct {
+	ID        uint      `json:"id" gorm:"column:id"`
+	JournalID string    `json:"journalId" gorm:"column:journal_id"`
+	Asset     string    `json:"asset" gorm:"column:asset"`
+	Account   string    `json:"account" gorm:"column:account"`
+	Debit     float64   `json:"debit" gorm:"column:debit"`
+	Credit    float64   `json:"credit" gorm:"column:credit"`
+	Kind      string    `json:"kind" gorm:"column:kind"`
+	OrderID   *uint     `json:"orderId,omitempty" gorm:"column:order_id"`
+	TradeID   *uint     `json:"tradeId,omitempty" gorm:"column:trade_id"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+// balances of an account next to the balances its ledger entries add up to
+type AccountDrift struct {
+	UserID        uint    `gorm:"column:user_id"`
+	Asset         string  `gorm:"column:asset"`
+	Balance       float64 `gorm:"column:balance"`
+	Locked        float64 `gorm:"column:locked"`
+	LedgerBalance float64 `gorm:"column:ledger_balance"`
+	LedgerLocked  float64 `gorm:"column:ledger_locked"`
+}
+
+// journal whose debits and credits of an asset don't add up
+type UnbalancedJournal struct {
+	JournalID string  `gorm:"column:journal_id"`
+	Asset     string  `gorm:"column:asset"`
+	Debit     float64 `gorm:"column:debit"`
+	Credit    float64 `gorm:"column:credit"`
+}
+
+type Symbol struct {
+	Symbol      string  `json:"symbol" gorm:"column:symbol"`
+	Status      string  `json:"status" gorm:"column:status"`
+	BaseAsset   string  `json:"baseAsset" gorm:"column:base_asset"`
+	QuoteAsset  string  `json:"quoteAsset" gorm:"column:quote_asset"`
+	TickSize    float64 `json:"tickSize" gorm:"column:tick_size"`
+	StepSize    float64 `json:"stepSize" gorm:"column:step_size"`
+	MinQty      float64 `json:"minQty" gorm:"column:min_qty"`
+	MaxQty      float64 `json:"maxQty" gorm:"column:max_qty"`
+	MinNotional float64 `json:"minNotional" gorm:"column:min_notional"`
+}
+
+type StopOut struct {
+	ID            uint       `json:"id" gorm:"column:id"`
+	UserID        uint       `json:"userId" gorm:"column:user_id"`
+	TradingDay    time.Time  `json:"tradingDay" gorm:"column:trading_day"`
+	Loss          float64    `json:"loss" gorm:"column:loss"`
+	LossLimit     float64    `json:"lossLimit" gorm:"column:loss_limit"`
+	RealizedPnl   float64    `json:"realizedPnl" gorm:"column:realized_pnl"`
+	UnrealizedPnl float64    `json:"unrealizedPnl" gorm:"column:unrealized_pnl"`
+	Flatten       bool       `json:"flatten" gorm:"column:flatten"`
+	ResetAt       *time.Time `json:"resetAt,omitempty" gorm:"column:reset_at"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"column:created_at"`
 }
@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/kannan112/mock-trading-platform-api/pkg/config"
+	service "github.com/kannan112/mock-trading-platform-api/pkg/usecase/interfaces"
+)
+
+// Worker run the background jobs of the platform next to the http server
+type Worker struct {
+	jobs []job
+}
+
+// job is run every interval until the worker is stopped, and once on start when atStart
+type job struct {
+	name     string
+	interval time.Duration
+	atStart  bool
+	run      func(ctx context.Context) error
+}
+
+func NewWorker(cfg config.Config, orderUseCase service.OrderUseCase, walletUseCase service.WalletUseCase,
+	portfolioUseCase service.PortfolioUseCase, symbolUseCase service.SymbolUseCase) *Worker {
+	return &Worker{
+		jobs: []job{
+			{name: "order matching", interval: cfg.OrderMatchInterval, run: orderUseCase.MatchOpenOrders},
+			{name: "order expiry", interval: cfg.OrderExpiryInterval, run: orderUseCase.ExpireOrders},
+			{name: "loss limit", interval: cfg.RiskLossCheckInterval, run: orderUseCase.EnforceLossLimits},
+			{name: "ledger reconciliation", interval: cfg.LedgerReconcileInterval, run: walletUseCase.ReconcileLedger},
+			{name: "equity snapshot", interval: cfg.EquitySnapshotInterval, run: portfolioUseCase.SnapshotEquity},
+			{name: "symbol refresh", interval: cfg.SymbolRefreshInterval, atStart: true, run: symbolUseCase.LoadSymbols},
+		},
+	}
+}
+
+// Start all the jobs, each one on its own goroutine until the ctx is done
+func (w *Worker) Start(ctx context.Context) {
+	for _, j := range w.jobs {
+		go j.start(ctx)
+	}
+}
+
+func (j job) start(ctx context.Context) {
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	if j.atStart {
+		if err := j.run(ctx); err != nil {
+			log.Printf("Failed to run %s job: %v", j.name, err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.run(ctx); err != nil {
+				log.Printf("Failed to run %s job: %v", j.name, err)
+			}
+		}
+	}
+}